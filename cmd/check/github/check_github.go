@@ -0,0 +1,129 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	syncergithub "github.com/falcosecurity/peribolos-syncer/internal/github"
+	"github.com/falcosecurity/peribolos-syncer/internal/output"
+	"github.com/falcosecurity/peribolos-syncer/internal/sync"
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+type options struct {
+	*sync.BindingOptions
+
+	github syncergithub.GitHubOptions
+	orgs   *orgs.Options
+}
+
+// New returns a new check github command.
+func New() *cobra.Command {
+	o := &options{
+		BindingOptions: sync.NewBindingOptions(),
+		github:         syncergithub.GitHubOptions{},
+		orgs:           &orgs.Options{},
+	}
+
+	cmd := &cobra.Command{
+		Use:     commandName,
+		Short:   commandShortDescription,
+		Example: commandExample,
+		RunE:    o.Run,
+	}
+
+	// Organization, teams, Owners and common sync options.
+	o.BindingOptions.AddPFlags(cmd.Flags())
+
+	// GitHub client options.
+	o.github.AddClientPFlags(cmd.Flags())
+
+	// Orgs config options.
+	o.orgs.AddPFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *options) validate() error {
+	// The roster file is read next to the Peribolos config, unless the Owners repository is specified.
+	o.UseConfigRepository(o.orgs)
+
+	if err := o.BindingOptions.Validate(); err != nil {
+		return err
+	}
+
+	return o.orgs.Validate()
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if err := o.validate(); err != nil {
+		return err
+	}
+
+	// Parameters are valid, usage is not needed anymore.
+	cmd.SilenceUsage = true
+
+	s, err := o.NewRemoteSyncer(&o.github, o.orgs)
+	if err != nil {
+		return err
+	}
+
+	plan, err := s.Plan()
+	if err != nil {
+		return err
+	}
+
+	if len(plan.Skipped) > 0 {
+		output.Print(fmt.Sprintf("Skipping teams not defined in the Peribolos config: %s", strings.Join(plan.Skipped, ", ")))
+	}
+
+	if plan.InSync() {
+		output.PrintFiltered(plan.Changes)
+		output.Print(fmt.Sprintf("The GitHub %s in sync.", o.subject()))
+
+		return nil
+	}
+
+	if err = output.PrintChanges(os.Stdout, output.FormatTable, plan.Drift(), &output.ConfigDiff{
+		Path:   o.orgs.ConfigPath,
+		Before: plan.Before,
+		After:  plan.After,
+	}); err != nil {
+		return err
+	}
+
+	output.PrintViolations(plan.Violations)
+
+	if err = output.PrintNewFindings(plan.Findings); err != nil {
+		return err
+	}
+
+	return output.NewExitError(output.ExitCodeDrift,
+		fmt.Sprintf("The GitHub %s not in sync with their OWNERS.", o.subject()))
+}
+
+// subject returns a human readable description of the checked teams.
+func (o *options) subject() string {
+	if o.MultiTeam() {
+		return fmt.Sprintf("teams of %s are", o.BindingsName())
+	}
+
+	return fmt.Sprintf("team %s is", o.GitHubTeam)
+}
@@ -0,0 +1,26 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+const (
+	commandName             = "github"
+	commandShortDescription = "Check that Peribolos config on remote GitHub repositories is in sync with the OWNERS"
+	commandExample          = `
+peribolos-syncer check github --org=acme --team=app-maintainers
+--peribolos-config-path=config/org.yaml --peribolos-config-repository=community --peribolos-config-git-ref=main
+--owners-repository=app --owners-git-ref=main
+--github-token-path=./bot_token
+`
+)
@@ -0,0 +1,92 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/output"
+	"github.com/falcosecurity/peribolos-syncer/internal/sync"
+)
+
+type options struct {
+	*sync.LocalOptions
+}
+
+// New returns a new check local command.
+func New() *cobra.Command {
+	o := &options{
+		LocalOptions: sync.NewLocalOptions(),
+	}
+
+	cmd := &cobra.Command{
+		Use:     commandName,
+		Short:   commandShortDescription,
+		Example: commandExample,
+		RunE:    o.Run,
+	}
+
+	// Owners file, config file, organization, team and common sync options.
+	o.LocalOptions.AddPFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if err := o.Validate(); err != nil {
+		return errors.Wrap(err, "error validating parameters")
+	}
+
+	// Parameters are valid, usage is not needed anymore.
+	cmd.SilenceUsage = true
+
+	s, err := o.NewSyncer()
+	if err != nil {
+		return err
+	}
+
+	plan, err := s.Plan()
+	if err != nil {
+		return err
+	}
+
+	if plan.InSync() {
+		output.PrintFiltered(plan.Changes)
+		output.Print(fmt.Sprintf("The GitHub team %s is in sync.", o.GitHubTeam))
+
+		return nil
+	}
+
+	if err = output.PrintChanges(os.Stdout, output.FormatTable, plan.Drift(), &output.ConfigDiff{
+		Path:   o.PeribolosConfigFilepath,
+		Before: plan.Before,
+		After:  plan.After,
+	}); err != nil {
+		return err
+	}
+
+	output.PrintViolations(plan.Violations)
+
+	if err = output.PrintNewFindings(plan.Findings); err != nil {
+		return err
+	}
+
+	return output.NewExitError(output.ExitCodeDrift,
+		fmt.Sprintf("The GitHub team %s is not in sync with %s.", o.GitHubTeam, o.SourceReference()))
+}
@@ -17,7 +17,9 @@ package cmd
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/falcosecurity/peribolos-syncer/cmd/check"
 	"github.com/falcosecurity/peribolos-syncer/cmd/sync"
+	"github.com/falcosecurity/peribolos-syncer/cmd/validate"
 	"github.com/falcosecurity/peribolos-syncer/cmd/version"
 	"github.com/falcosecurity/peribolos-syncer/internal/output"
 )
@@ -29,8 +31,13 @@ func New() *cobra.Command {
 	cmd.Long = CommandLongDescription
 	cmd.DisableAutoGenTag = true
 
+	// Errors are printed by output.ExitOnErr.
+	cmd.SilenceErrors = true
+
 	// Add subcommands.
 	cmd.AddCommand(sync.New())
+	cmd.AddCommand(check.New())
+	cmd.AddCommand(validate.New())
 	cmd.AddCommand(version.New())
 
 	return cmd
@@ -30,5 +30,6 @@ peribolos-syncer sync github --org=acme --team=app-maintainers
 	ownersDoc           = "https://docs.prow.k8s.io/docs/components/plugins/approve/approvers/#overview"
 	peribolosConfigFile = "org.yaml"
 
-	modeConfigFile = 0o644
+	branchNameFormat        = "peribolos-syncer/%s/%s"
+	pullRequestMarkerFormat = "<!-- peribolos-syncer: %s/%s -->"
 )
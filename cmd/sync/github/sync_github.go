@@ -16,33 +16,25 @@ package github
 
 import (
 	"fmt"
-	"os"
-	"path"
 	"strings"
-	"unicode"
 
 	"github.com/go-git/go-git/v5"
 	gitobject "github.com/go-git/go-git/v5/plumbing/object"
-	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	"golang.org/x/exp/maps"
-	peribolos "k8s.io/test-infra/prow/config/org"
-	"k8s.io/test-infra/prow/github"
-	"k8s.io/test-infra/prow/repoowners"
-	"sigs.k8s.io/yaml"
 
-	syncergit "github.com/falcosecurity/peribolos-syncer/internal/git"
 	syncergithub "github.com/falcosecurity/peribolos-syncer/internal/github"
 	"github.com/falcosecurity/peribolos-syncer/internal/output"
 	"github.com/falcosecurity/peribolos-syncer/internal/owners"
+	"github.com/falcosecurity/peribolos-syncer/internal/source"
 	"github.com/falcosecurity/peribolos-syncer/internal/sync"
 	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
 	"github.com/falcosecurity/peribolos-syncer/pkg/pgp"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
 )
 
 type options struct {
-	*sync.CommonOptions
+	*sync.BindingOptions
 
 	author            gitobject.Signature
 	privateGPGKeyPath string
@@ -50,7 +42,6 @@ type options struct {
 
 	github syncergithub.GitHubOptions
 	orgs   *orgs.Options
-	owners *owners.OwnersLoadingOptions
 
 	git.ListOptions
 }
@@ -58,11 +49,10 @@ type options struct {
 // New returns a new sync github command.
 func New() *cobra.Command {
 	o := &options{
-		CommonOptions: &sync.CommonOptions{},
-		author:        gitobject.Signature{},
-		github:        syncergithub.GitHubOptions{},
-		owners:        &owners.OwnersLoadingOptions{},
-		orgs:          &orgs.Options{},
+		BindingOptions: sync.NewBindingOptions(),
+		author:         gitobject.Signature{},
+		github:         syncergithub.GitHubOptions{},
+		orgs:           &orgs.Options{},
 	}
 
 	cmd := &cobra.Command{
@@ -72,9 +62,8 @@ func New() *cobra.Command {
 		RunE:    o.Run,
 	}
 
-	// Organization sync options.
-	cmd.Flags().StringVar(&o.GitHubOrg, "org", "", "The name of the GitHub organization to update configuration for")
-	cmd.Flags().StringVar(&o.GitHubTeam, "team", "", "The name of the GitHub team to update configuration for")
+	// Organization, teams, Owners and common sync options.
+	o.BindingOptions.AddPFlags(cmd.Flags())
 
 	// Git author options.
 	cmd.Flags().StringVar(&o.author.Name, "git-author-name", "", "The Git author name with which write commits for the update of the Peribolos config")
@@ -85,9 +74,6 @@ func New() *cobra.Command {
 	// GitHub options.
 	o.github.AddPFlags(cmd.Flags())
 
-	// Owners options.
-	o.owners.AddPFlags(cmd.Flags())
-
 	// Orgs config options.
 	o.orgs.AddPFlags(cmd.Flags())
 
@@ -95,12 +81,11 @@ func New() *cobra.Command {
 }
 
 func (o *options) validate() error {
-	if o.GitHubOrg == "" {
-		return errors.New("github organization name is empty")
-	}
+	// The roster file is read next to the Peribolos config, unless the Owners repository is specified.
+	o.UseConfigRepository(o.orgs)
 
-	if o.GitHubTeam == "" {
-		return errors.New("github team name is empty")
+	if err := o.BindingOptions.Validate(); err != nil {
+		return err
 	}
 
 	if o.author.Name == "" {
@@ -119,10 +104,6 @@ func (o *options) validate() error {
 		return errors.New("git author private pgp key path cannot be empty")
 	}
 
-	if err := o.owners.Validate(); err != nil {
-		return err
-	}
-
 	if err := o.orgs.Validate(); err != nil {
 		return err
 	}
@@ -135,200 +116,224 @@ func (o *options) validate() error {
 }
 
 //nolint:funlen
-func (o *options) Run(_ *cobra.Command, _ []string) error {
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
 	if err := o.validate(); err != nil {
 		return err
 	}
 
-	token, err := getTokenFromFile(o.github.TokenPath)
+	// Parameters are valid, usage is not needed anymore.
+	cmd.SilenceUsage = true
+
+	githubClient, token, err := o.github.NewClientFromTokenFile()
 	if err != nil {
-		return errors.Wrap(err, "error reading token from file")
+		return err
 	}
 
-	// Build GitHub client.
-	githubClient, err := o.github.GitHubClientWithAccessToken(token)
+	gitClientFactory, err := o.github.GetGitClientFactory()
 	if err != nil {
-		return errors.Wrap(err, "error generating github client with specified access token")
+		return errors.Wrap(err, "error building git client gitclientfactory")
 	}
 
-	// Load Owners hierarchy from specified repository.
-	owners, err := o.loadOwnersFromGithub(githubClient)
+	// Load the bindings between the teams and their source of truth.
+	bindings, err := o.LoadBindings(&source.Clients{
+		Owners: owners.NewClient(githubClient, gitClientFactory),
+		Files:  githubClient,
+		Host:   o.github.Host,
+	})
 	if err != nil {
 		return err
 	}
 
-	// Load specified people from the Owners structure.
-	people := o.loadPeopleFromOwners(owners)
-
-	// Clone the peribolos config repository.
-	repo, worktree, local, err := o.github.ForkRepository(
-		githubClient, o.GitHubOrg, o.orgs.ConfigRepo, token)
+	mapping, err := o.LoadHandleMapping()
 	if err != nil {
-		return errors.Wrap(err, "error forking the config repository")
+		return err
 	}
 
-	// Load GitHub orgs config from the git working tree's filesystem.
-	config, err := orgs.LoadConfigFromFilesystem(worktree.Filesystem, o.orgs.ConfigPath)
+	guardrails, err := o.Guardrails()
 	if err != nil {
-		return errors.Wrap(err, "error loading the config")
+		return err
 	}
 
-	// Create an ephemeral branch for the changes.
-	ref, err := syncergit.NewEphemeralGitBranch(repo, worktree)
+	// Generate a PGP entity to sign the git commits.
+	pgpEntity, err := pgp.NewPGPEntity(o.author.Name, o.author.Email, o.publicGPGKeyPath, o.privateGPGKeyPath)
 	if err != nil {
-		return errors.Wrap(err, "error creating new branch for changes on the config")
+		return errors.Wrap(err, "error generating the pgp entity")
 	}
 
-	// Synchronize the Github Team config with Approvers.
-	if err = orgs.AddTeamMembers(config, o.GitHubOrg, o.GitHubTeam, people); err != nil {
-		return errors.Wrap(err, "error updating maintainers github team from leaf approvers")
+	// The peribolos config is stored on the fork, starting from the current upstream config, on a branch named after
+	// the sync so that later syncs update the same pull request.
+	fork := &syncergithub.Fork{
+		Options:   &o.github,
+		Client:    githubClient,
+		Token:     token,
+		Org:       o.GitHubOrg,
+		Repo:      o.orgs.ConfigRepo,
+		Path:      o.orgs.ConfigPath,
+		BaseRef:   o.orgs.ConfigBaseRef,
+		Branch:    fmt.Sprintf(branchNameFormat, o.GitHubOrg, o.SyncName()),
+		Marker:    fmt.Sprintf(pullRequestMarkerFormat, o.GitHubOrg, o.SyncName()),
+		Author:    &o.author,
+		PGPEntity: pgpEntity,
 	}
-
-	// Flush updated config to local working copy.
-	if err = o.flushConfig(config, local); err != nil {
-		return errors.Wrap(err, "error writing updated peribolos config")
+	fork.Describe = func(plan *syncer.Plan) *syncergithub.Description {
+		return &syncergithub.Description{
+			CommitMessage: o.commitMessage(plan.Drift()),
+			Title:         o.pullRequestTitle(),
+			Body:          o.pullRequestBody(plan.Drift(), plan.Changes, fork.Marker),
+		}
 	}
 
-	// Store the change in a commitAll with a log.
-	commitMsg := fmt.Sprintf(`chore(%s): update %s team members
-
-The update reflects the content of the related repository's OWNERS tree.
-%s
-
-Signed-off-by: %s <%s>
-`, peribolosConfigFile, o.GitHubTeam, syncerSignature, o.author.Name, o.author.Email)
-
-	// Generate a PGP entity to sign the git commits.
-	pgpEntity, err := pgp.NewPGPEntity(o.author.Name, o.author.Email, o.publicGPGKeyPath, o.privateGPGKeyPath)
+	// Synchronize the Github Teams config with their Owners.
+	plan, err := (&syncer.Syncer{
+		Bindings:      bindings,
+		Store:         fork,
+		Publisher:     fork,
+		Reconcile:     o.Reconcile,
+		Handles:       mapping,
+		Guardrails:    guardrails,
+		OrgMembership: syncer.OrgMembership(o.OrgMembership),
+		Validate:      true,
+	}).Sync()
 	if err != nil {
-		return errors.Wrap(err, "error generating the pgp entity")
+		return err
 	}
 
-	// Stage the change to the config and create a commit for it.
-	if err = syncergit.StageAndCommit(repo, worktree, &o.author, pgpEntity, o.orgs.ConfigPath, commitMsg); err != nil {
-		return errors.Wrap(err, "error committing the changes on config")
+	if len(plan.Skipped) > 0 {
+		output.Print(fmt.Sprintf("Skipping teams not defined in the Peribolos config: %s", strings.Join(plan.Skipped, ", ")))
 	}
 
-	// Skip push to remote and pull request creation when dry run.
-	if !o.github.DryRun {
-		// Push the new branch to the remote.
-		if err := repo.Push(&git.PushOptions{
-			Auth: &githttp.BasicAuth{
-				Username: o.github.Username,
-				Password: token,
-			},
-		}); err != nil {
-			return errors.Wrap(err, "error pushing config update git branch")
-		}
+	output.PrintFiltered(plan.Changes)
+	output.PrintViolations(plan.Violations)
 
-		// Create a Pull Request on GitHub.
-		pr, err := githubClient.CreatePullRequest(
-			o.GitHubOrg,
-			o.orgs.ConfigRepo,
-			fmt.Sprintf("Sync Github Team %s with %s owners", o.GitHubTeam, o.owners.RepositoryName),
-			fmt.Sprintf(`This PR synchronizes the Github Team %s with the leaf approvers declared in %s repository's [OWNERS](%s) file.
-
-%s
-`, o.GitHubTeam, o.owners.RepositoryName, ownersDoc, syncerSignature),
-			fmt.Sprintf("%s:%s", o.github.Username, ref),
-			o.orgs.ConfigBaseRef,
-			false,
-		)
-		if err != nil {
-			return errors.Wrap(err, "error creating github pull request")
-		}
+	// The commit and the pull request are skipped when there is nothing to change.
+	if plan.InSync() {
+		return output.NewExitError(output.ExitCodeNoChanges,
+			fmt.Sprintf("The GitHub %s already in sync.", o.subject()))
+	}
+
+	printChanges(plan.Drift())
 
-		output.Print(
-			fmt.Sprintf("A Pull Request has been opened: https://%s/%s/%s/pull/%d",
-				o.github.Host, o.GitHubOrg, o.orgs.ConfigRepo, pr),
-		)
+	if o.github.DryRun {
+		output.Print("Skipping pull request.")
 
 		return nil
 	}
 
-	output.Print("Skipping pull request.")
+	action := "opened"
+	if fork.Existing {
+		action = "updated"
+	}
+
+	output.Print(
+		fmt.Sprintf("A Pull Request has been %s: https://%s/%s/%s/pull/%d",
+			action, o.github.Host, o.GitHubOrg, o.orgs.ConfigRepo, fork.PullRequest),
+	)
 
 	return nil
 }
 
-func (o *options) loadOwnersFromGithub(githubClient github.Client) (repoowners.RepoOwner, error) {
-	gitClientFactory, err := o.github.GetGitClientFactory()
-	if err != nil {
-		return nil, errors.Wrap(err, "error building git client gitclientfactory")
+// subject returns a human readable description of the synchronized teams.
+func (o *options) subject() string {
+	if o.MultiTeam() {
+		return fmt.Sprintf("teams of %s are", o.BindingsName())
 	}
 
-	ownersClient := owners.NewClient(githubClient, gitClientFactory)
+	return fmt.Sprintf("team %s is", o.GitHubTeam)
+}
 
-	// Load Owners hierarchy from specified repository.
-	owners, err := ownersClient.LoadRepoOwners(o.GitHubOrg, o.owners.RepositoryName, o.owners.GitRef)
-	if err != nil {
-		return nil, errors.Wrap(err, "error loading owners from repository")
+func (o *options) commitMessage(changes []*orgs.TeamChanges) string {
+	title := fmt.Sprintf("chore(%s): update %s team members", peribolosConfigFile, o.GitHubTeam)
+	if o.MultiTeam() {
+		title = fmt.Sprintf("chore(%s): update %s teams members", peribolosConfigFile, o.BindingsName())
 	}
 
-	return owners, nil
+	var b strings.Builder
+
+	for _, c := range changes {
+		if o.MultiTeam() {
+			fmt.Fprintf(&b, "%s:\n", c.Team)
+		}
+
+		fmt.Fprintf(&b, "%s\n", c)
+	}
+
+	return fmt.Sprintf(`%s
+
+The update reflects %s.
+
+%s%s
+
+Signed-off-by: %s <%s>
+`, title, o.SourceReference(o.github.Host), b.String(), syncerSignature, o.author.Name, o.author.Email)
 }
 
-func (o *options) loadPeopleFromOwners(owners repoowners.RepoOwner) []string {
-	var people []string
+func (o *options) pullRequestTitle() string {
+	if o.MultiTeam() {
+		return fmt.Sprintf("Sync Github Teams of %s with their owners", o.BindingsName())
+	}
+
+	return fmt.Sprintf("Sync Github Team %s with %s", o.GitHubTeam, o.Source.Reference(o.Owners.RepositoryName))
+}
+
+func (o *options) pullRequestBody(changes, all []*orgs.TeamChanges, marker string) string {
+	var b strings.Builder
 
 	switch {
-	// Limiting the scope of the roles.
-	case o.owners.ConfigPath != "":
-		//nolint:gocritic
-		if o.owners.ApproversOnly {
-			// Approvers of the subpart of the repository.
-			people = maps.Keys(owners.Approvers(o.owners.ConfigPath).Set())
-		} else if o.owners.ReviewersOnly {
-			// Reviewers of the subpart of the repository.
-			people = maps.Keys(owners.Reviewers(o.owners.ConfigPath).Set())
-		} else {
-			// Both approvers and reviewers of the subpart of the repository.
-			people = maps.Keys(owners.Approvers(o.owners.ConfigPath).
-				Union(owners.Reviewers(o.owners.ConfigPath)).Set())
+	case o.Sigs.Enabled():
+		fmt.Fprintf(&b, "This PR synchronizes the Github Teams of the groups of %s with their leads and the approvers of their subprojects' [OWNERS](%s) files.\n", o.BindingsName(), ownersDoc)
+
+		for _, c := range changes {
+			fmt.Fprintf(&b, "\n#### %s\n\n```diff\n%s```\n", c.Team, c)
+		}
+	case o.Manifest != nil:
+		fmt.Fprintf(&b, "This PR synchronizes the Github Teams of the %s with the sources of truth of their bindings.\n", o.BindingsName())
+
+		for _, c := range changes {
+			fmt.Fprintf(&b, "\n#### %s\n\n```diff\n%s```\n", c.Team, c)
 		}
-	// Approvers of the whole repository.
-	case o.owners.ApproversOnly:
-		people = maps.Keys(owners.AllApprovers())
-	// Reviewers of the whole repository.
-	case o.owners.ReviewersOnly:
-		people = maps.Keys(owners.AllReviewers())
-	// Both approvers and reviewers of the whole repository.
 	default:
-		people = maps.Keys(owners.AllOwners())
+		fmt.Fprintf(&b, "This PR synchronizes the Github Team %s with %s.\n", o.GitHubTeam, o.SourceReference(o.github.Host))
+
+		for _, c := range changes {
+			fmt.Fprintf(&b, "\n```diff\n%s```\n", c)
+		}
 	}
 
-	return people
+	// Report the handles filtered out of every team, including the ones in sync.
+	writeFiltered(&b, all)
+
+	fmt.Fprintf(&b, "\n%s\n\n%s\n", syncerSignature, marker)
+
+	return b.String()
 }
 
-func (o *options) flushConfig(config *peribolos.FullConfig, configPath string) error {
-	b, err := yaml.Marshal(config)
-	if err != nil {
-		return errors.Wrap(err, "error recompiling the peribolos config")
-	}
+// writeFiltered writes the handles that the deny and protect patterns filtered out of the teams, if any.
+func writeFiltered(b *strings.Builder, changes []*orgs.TeamChanges) {
+	header := false
 
-	if err = os.WriteFile(path.Join(configPath, o.orgs.ConfigPath), b, modeConfigFile); err != nil {
-		return errors.Wrap(err, "error writing the recompiled peribolos config")
-	}
+	for _, c := range changes {
+		if c.Filtered.Empty() {
+			continue
+		}
 
-	return nil
-}
+		if !header {
+			fmt.Fprintf(b, "\n#### Filtered out\n\nThe following handles have been filtered out by the deny and protect patterns:\n\n")
 
-func getTokenFromFile(path string) (string, error) {
-	token, err := os.ReadFile(path)
-	if err != nil {
-		return "", errors.Wrap(err, "error reading token file")
-	}
+			header = true
+		}
 
-	return removeNonPrintableChars(string(token)), nil
-}
+		if len(c.Denied) > 0 {
+			fmt.Fprintf(b, "* %s, denied: %s\n", c.Team, strings.Join(c.Denied, ", "))
+		}
 
-func removeNonPrintableChars(s string) string {
-	return strings.Map(func(r rune) rune {
-		switch {
-		case unicode.IsPrint(r):
-			return r
-		default:
-			return -1
+		if len(c.Protected) > 0 {
+			fmt.Fprintf(b, "* %s, protected: %s\n", c.Team, strings.Join(c.Protected, ", "))
 		}
-	}, s)
+	}
+}
+
+func printChanges(changes []*orgs.TeamChanges) {
+	for _, c := range changes {
+		output.Print(fmt.Sprintf("Team %s:\n%s", c.Team, c))
+	}
 }
@@ -16,29 +16,22 @@ package local
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	"k8s.io/test-infra/prow/repoowners"
-	"sigs.k8s.io/yaml"
 
 	"github.com/falcosecurity/peribolos-syncer/internal/output"
 	"github.com/falcosecurity/peribolos-syncer/internal/sync"
-	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
 )
 
 type options struct {
-	peribolosConfigFilepath string
-	ownersFilepath          string
-
-	*sync.CommonOptions
+	*sync.LocalOptions
 }
 
 // New returns a new sync local command.
 func New() *cobra.Command {
 	o := &options{
-		CommonOptions: &sync.CommonOptions{},
+		LocalOptions: sync.NewLocalOptions(),
 	}
 
 	cmd := &cobra.Command{
@@ -49,68 +42,41 @@ func New() *cobra.Command {
 
 	cmd.RunE = o.Run
 
-	cmd.Flags().StringVarP(&o.ownersFilepath, flagOwnersFilePath, "o", defaultOwnersFilepath, "The path to the OWNERS file")
-	cmd.Flags().StringVarP(&o.peribolosConfigFilepath, flagPeribolosConfigFilepath, "c", defaultPeribolosConfigFilepath, "The path to the Peribolos org.yaml file")
-	cmd.Flags().StringVar(&o.GitHubOrg, "org", "", "The name of the GitHub organization to update")
-	cmd.Flags().StringVar(&o.GitHubTeam, "team", "", "The name of the GitHub organization to update")
+	// Owners file, config file, organization, team and common sync options.
+	o.LocalOptions.AddPFlags(cmd.Flags())
 
 	return cmd
 }
 
-func (o *options) validate() error {
-	if o.GitHubOrg == "" {
-		//nolint:goerr113
-		return fmt.Errorf("org name is empty")
-	}
-
-	if o.GitHubTeam == "" {
-		//nolint:goerr113
-		return fmt.Errorf("team name is empty")
-	}
-
-	return nil
-}
-
-func (o *options) Run(_ *cobra.Command, _ []string) error {
-	if err := o.validate(); err != nil {
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if err := o.Validate(); err != nil {
 		return errors.Wrap(err, "error validating parameters")
 	}
 
-	b, err := os.ReadFile(o.ownersFilepath)
-	if err != nil {
-		return errors.Wrap(err, "error reading OWNERS file")
-	}
+	// Parameters are valid, usage is not needed anymore.
+	cmd.SilenceUsage = true
 
-	owners, err := repoowners.LoadSimpleConfig(b)
+	// Synchronize the GitHub Team config with its Owners, leaving the config untouched when there is nothing to change.
+	s, err := o.NewSyncer()
 	if err != nil {
-		return errors.Wrap(err, "error unmarshaling owners")
+		return err
 	}
 
-	b, err = os.ReadFile(o.peribolosConfigFilepath)
+	plan, err := s.Sync()
 	if err != nil {
-		return errors.Wrap(err, "error reading Peribolos config file")
-	}
-
-	orgsConfig := orgs.NewConfig()
-
-	if err = yaml.Unmarshal(b, orgsConfig); err != nil {
-		return errors.Wrap(err, "error unmarshaling Peribolos config")
+		return err
 	}
 
-	if err = orgs.AddTeamMembers(orgsConfig, o.GitHubOrg, o.GitHubTeam, owners.Approvers); err != nil {
-		return errors.Wrap(err, "error updating Peribolos' maintainers from OWNERS's approvers")
-	}
-
-	compiled, err := yaml.Marshal(orgsConfig)
-	if err != nil {
-		return errors.Wrap(err, "error recompiling the Peribolos config")
-	}
+	output.PrintFiltered(plan.Changes)
+	output.PrintViolations(plan.Violations)
 
-	if err = os.WriteFile(o.peribolosConfigFilepath, compiled, FilePerm); err != nil {
-		return errors.Wrap(err, "error writing the recompiled Peribolos config")
+	if plan.InSync() {
+		return output.NewExitError(output.ExitCodeNoChanges,
+			fmt.Sprintf("The GitHub team %s is already in sync.", o.GitHubTeam))
 	}
 
 	output.Print("The Peribolos configuration has been updated.")
+	output.Print(plan.Changes[0].String())
 
 	return nil
 }
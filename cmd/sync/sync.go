@@ -19,6 +19,7 @@ import (
 
 	"github.com/falcosecurity/peribolos-syncer/cmd/sync/github"
 	"github.com/falcosecurity/peribolos-syncer/cmd/sync/local"
+	"github.com/falcosecurity/peribolos-syncer/cmd/sync/plan"
 )
 
 // New returns a new root command.
@@ -31,6 +32,7 @@ func New() *cobra.Command {
 	// Add sync subcommand.
 	cmd.AddCommand(local.New())
 	cmd.AddCommand(github.New())
+	cmd.AddCommand(plan.New())
 
 	return cmd
 }
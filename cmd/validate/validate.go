@@ -0,0 +1,93 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/output"
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+const defaultPeribolosConfigFilepath = "org.yaml"
+
+type options struct {
+	peribolosConfigFilepath string
+	format                  string
+}
+
+// New returns a new validate command.
+func New() *cobra.Command {
+	o := &options{}
+
+	cmd := &cobra.Command{
+		Use:     commandName,
+		Short:   commandShortDescription,
+		Example: commandExample,
+		RunE:    o.Run,
+	}
+
+	cmd.Flags().StringVarP(&o.peribolosConfigFilepath, "orgs-config", "c", defaultPeribolosConfigFilepath, "The path to the Peribolos org.yaml file")
+	cmd.Flags().StringVar(&o.format, "output", output.FormatTable, fmt.Sprintf("The output format of the findings, one of %v", output.FindingsFormats))
+
+	return cmd
+}
+
+func (o *options) Run(cmd *cobra.Command, _ []string) error {
+	if err := output.ValidateFindingsFormat(o.format); err != nil {
+		return errors.Wrap(err, "error validating parameters")
+	}
+
+	// Parameters are valid, usage is not needed anymore.
+	cmd.SilenceUsage = true
+
+	b, err := os.ReadFile(o.peribolosConfigFilepath)
+	if err != nil {
+		return errors.Wrap(err, "error reading the peribolos config")
+	}
+
+	config, err := orgs.LoadConfig(b)
+	if err != nil {
+		return err
+	}
+
+	findings := orgs.Validate(config)
+
+	if len(findings) == 0 && o.format == output.FormatTable {
+		output.Print("The Peribolos config is valid.")
+
+		return nil
+	}
+
+	if err = output.PrintFindings(os.Stdout, o.format, findings); err != nil {
+		return err
+	}
+
+	if len(findings) == 0 {
+		return nil
+	}
+
+	// The findings printed as JSON are not followed by any message, so that they can be parsed.
+	message := ""
+	if o.format == output.FormatTable {
+		message = fmt.Sprintf("The Peribolos config has %d findings.", len(findings))
+	}
+
+	return output.NewExitError(output.ExitCodeInvalid, message)
+}
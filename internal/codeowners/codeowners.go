@@ -0,0 +1,211 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// Locations represents the paths at which GitHub looks for the CODEOWNERS file, by precedence.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+var (
+	// The user and team regexps only capture the candidate logins, validated by isUser and isTeam.
+	userRegexp = regexp.MustCompile(`^@([a-zA-Z0-9-]+)$`)
+	teamRegexp = regexp.MustCompile(`^@([a-zA-Z0-9-]+)/([a-zA-Z0-9_.-]+)$`)
+	slugRegexp = regexp.MustCompile(`[^a-z0-9_]+`)
+)
+
+// Rule represents a CODEOWNERS rule, assigning owners to the paths matching a pattern.
+type Rule struct {
+	// Pattern represents the gitignore-style pattern of the paths.
+	Pattern string
+
+	// Owners represents the owners of the paths: either @user or @org/team references.
+	Owners []string
+
+	matcher gitignore.Pattern
+}
+
+// CodeOwners represents a parsed CODEOWNERS file.
+type CodeOwners struct {
+	// Rules represents the rules of the file, in order.
+	Rules []Rule
+}
+
+// Parse parses the content of a CODEOWNERS file. Email owners are ignored, as they cannot be mapped to GitHub handles.
+// It possibly returns an error.
+func Parse(b []byte) (*CodeOwners, error) {
+	c := &CodeOwners{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		rule := Rule{
+			Pattern: fields[0],
+			Owners:  []string{},
+			matcher: gitignore.ParsePattern(fields[0], nil),
+		}
+
+		for _, owner := range fields[1:] {
+			switch {
+			case isUser(owner), isTeam(owner):
+				rule.Owners = append(rule.Owners, owner)
+			case strings.Contains(owner, "@") && !strings.HasPrefix(owner, "@"):
+				// Email owner.
+				continue
+			default:
+				//nolint:goerr113
+				return nil, fmt.Errorf("line %d: invalid owner %q", line, owner)
+			}
+		}
+
+		c.Rules = append(c.Rules, rule)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "error reading codeowners")
+	}
+
+	return c, nil
+}
+
+// OwnersOf returns the owners of the specified path, that are the ones of the last matching rule.
+func (c *CodeOwners) OwnersOf(path string) []string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].matcher.Match(parts, false) == gitignore.Exclude {
+			return c.Rules[i].Owners
+		}
+	}
+
+	return []string{}
+}
+
+// All returns the owners of every rule, without duplicates.
+func (c *CodeOwners) All() []string {
+	owners := sets.NewString()
+
+	for _, r := range c.Rules {
+		owners.Insert(r.Owners...)
+	}
+
+	return owners.List()
+}
+
+// Resolve returns the GitHub handles of the specified owners, expanding the @org/team references with the
+// maintainers and members of the Teams defined in the Peribolos config. It possibly returns an error.
+func Resolve(owners []string, config *peribolos.FullConfig) ([]string, error) {
+	handles := sets.NewString()
+
+	for _, owner := range owners {
+		if isUser(owner) {
+			handles.Insert(strings.ToLower(strings.TrimPrefix(owner, "@")))
+
+			continue
+		}
+
+		if !isTeam(owner) {
+			//nolint:goerr113
+			return nil, fmt.Errorf("invalid owner %q", owner)
+		}
+
+		m := teamRegexp.FindStringSubmatch(owner)
+
+		team, ok := findTeam(config, m[1], m[2])
+		if !ok {
+			//nolint:goerr113
+			return nil, fmt.Errorf("team %s of organization %s not found in peribolos config", m[2], m[1])
+		}
+
+		for _, h := range append(append([]string{}, team.Maintainers...), team.Members...) {
+			handles.Insert(strings.ToLower(h))
+		}
+	}
+
+	return handles.List(), nil
+}
+
+// isUser returns whether the owner is a @login reference to a GitHub user.
+func isUser(owner string) bool {
+	m := userRegexp.FindStringSubmatch(owner)
+
+	return m != nil && syncer.IsValidLogin(m[1])
+}
+
+// isTeam returns whether the owner is a @org/team reference to a GitHub team.
+func isTeam(owner string) bool {
+	m := teamRegexp.FindStringSubmatch(owner)
+
+	return m != nil && syncer.IsValidLogin(m[1])
+}
+
+// findTeam returns the Team of the specified organization, looking it up by its slug, case-insensitively.
+func findTeam(config *peribolos.FullConfig, org, slug string) (peribolos.Team, bool) {
+	for orgName, orgConfig := range config.Orgs {
+		if !strings.EqualFold(orgName, org) {
+			continue
+		}
+
+		if team, ok := findTeamBySlug(orgConfig.Teams, slug); ok {
+			return team, true
+		}
+	}
+
+	return peribolos.Team{}, false
+}
+
+// findTeamBySlug returns the Team with the specified slug, looking it up in the child teams too, as GitHub team slugs
+// are unique within the organization.
+func findTeamBySlug(teams map[string]peribolos.Team, slug string) (peribolos.Team, bool) {
+	for name, team := range teams {
+		if strings.EqualFold(Slug(name), slug) {
+			return team, true
+		}
+
+		if child, ok := findTeamBySlug(team.Children, slug); ok {
+			return child, true
+		}
+	}
+
+	return peribolos.Team{}, false
+}
+
+// Slug returns the GitHub slug of a Team name.
+func Slug(name string) string {
+	return strings.Trim(slugRegexp.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeowners_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/codeowners"
+)
+
+var _ = Describe("Parsing CODEOWNERS", func() {
+	var (
+		err     error
+		content string
+		c       *codeowners.CodeOwners
+	)
+
+	BeforeEach(func() {
+		content = `# Default owners.
+*           @alice @acme/core-maintainers
+
+/docs/      @bob docs@acme.org
+*.go        @charlie # Go code.
+`
+	})
+
+	JustBeforeEach(func() {
+		c, err = codeowners.Parse([]byte(content))
+	})
+
+	It("should parse the rules", func() {
+		Expect(err).To(Succeed())
+		Expect(c.Rules).To(HaveLen(3))
+	})
+
+	It("should return every owner", func() {
+		Expect(c.All()).To(Equal([]string{"@acme/core-maintainers", "@alice", "@bob", "@charlie"}))
+	})
+
+	It("should return the owners of the last matching rule", func() {
+		Expect(c.OwnersOf("docs/index.md")).To(Equal([]string{"@bob"}))
+		Expect(c.OwnersOf("docs/gen.go")).To(Equal([]string{"@charlie"}))
+		Expect(c.OwnersOf("README.md")).To(Equal([]string{"@alice", "@acme/core-maintainers"}))
+	})
+
+	Context("with an invalid owner", func() {
+		BeforeEach(func() {
+			content = "*  @alice\n/docs/  bob\n"
+		})
+
+		It("should error with the line number", func() {
+			Expect(err).To(MatchError(ContainSubstring("line 2")))
+		})
+	})
+
+	Context("with an owner that is not a valid GitHub login", func() {
+		BeforeEach(func() {
+			content = "*  @alice\n/docs/  @bob--smith\n"
+		})
+
+		It("should error with the line number", func() {
+			Expect(err).To(MatchError(`line 2: invalid owner "@bob--smith"`))
+		})
+	})
+})
+
+var _ = Describe("Resolving code owners", func() {
+	var config *peribolos.FullConfig
+
+	BeforeEach(func() {
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{
+			"acme": {Teams: map[string]peribolos.Team{
+				"Core Maintainers": {Maintainers: []string{"dave"}, Members: []string{"Erin"}, Children: map[string]peribolos.Team{
+					"infra": {Members: []string{"frank"}},
+				}},
+			}},
+		}}
+	})
+
+	It("should expand the teams by slug", func() {
+		Expect(codeowners.Resolve([]string{"@Alice", "@acme/core-maintainers"}, config)).
+			To(Equal([]string{"alice", "dave", "erin"}))
+	})
+
+	It("should expand the child teams by slug", func() {
+		Expect(codeowners.Resolve([]string{"@acme/infra"}, config)).To(Equal([]string{"frank"}))
+	})
+
+	It("should error on unknown teams", func() {
+		_, err := codeowners.Resolve([]string{"@acme/unknown"}, config)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,111 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	peribolos "k8s.io/test-infra/prow/config/org"
+	prowgithub "k8s.io/test-infra/prow/github"
+
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// fileGetter represents the GitHub client operation needed to read a remote file.
+type fileGetter interface {
+	GetFile(org, repo, filepath, commit string) ([]byte, error)
+}
+
+// LocalSource represents the CODEOWNERS file of a local checkout of a git repository, as a people source of truth.
+// The code owners are considered approvers.
+type LocalSource struct {
+	// Dir represents the path to the local checkout.
+	Dir string
+
+	// Path represents the path whose code owners are considered. When empty, the owners of every rule are.
+	Path string
+}
+
+// LoadPeople returns the code owners of the local CODEOWNERS file. It possibly returns an error.
+func (s *LocalSource) LoadPeople(config *peribolos.FullConfig) (*syncer.People, error) {
+	for _, location := range Locations {
+		b, err := os.ReadFile(filepath.Join(s.Dir, location))
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading codeowners file")
+		}
+
+		return loadPeople(b, s.Path, config)
+	}
+
+	return nil, errors.New("codeowners file not found")
+}
+
+// RemoteSource represents the CODEOWNERS file of a remote GitHub repository, as a people source of truth.
+// The code owners are considered approvers.
+type RemoteSource struct {
+	Client fileGetter
+
+	Org    string
+	Repo   string
+	GitRef string
+
+	// Path represents the path whose code owners are considered. When empty, the owners of every rule are.
+	Path string
+}
+
+// LoadPeople returns the code owners of the remote CODEOWNERS file. It possibly returns an error.
+func (s *RemoteSource) LoadPeople(config *peribolos.FullConfig) (*syncer.People, error) {
+	for _, location := range Locations {
+		b, err := s.Client.GetFile(s.Org, s.Repo, location, s.GitRef)
+
+		var notFound *prowgithub.FileNotFound
+		if errors.As(err, &notFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading codeowners file from repository %s", s.Repo)
+		}
+
+		return loadPeople(b, s.Path, config)
+	}
+
+	return nil, errors.Errorf("codeowners file not found in repository %s", s.Repo)
+}
+
+func loadPeople(b []byte, path string, config *peribolos.FullConfig) (*syncer.People, error) {
+	c, err := Parse(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing codeowners file")
+	}
+
+	owners := c.All()
+	if path != "" {
+		owners = c.OwnersOf(path)
+	}
+
+	handles, err := Resolve(owners, config)
+	if err != nil {
+		return nil, errors.Wrap(err, "error resolving code owners")
+	}
+
+	return &syncer.People{Approvers: handles}, nil
+}
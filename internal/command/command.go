@@ -0,0 +1,227 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	peribolos "k8s.io/test-infra/prow/config/org"
+	prowgithub "k8s.io/test-infra/prow/github"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+const (
+	// RoleApprover represents the role of the people that are approvers, that is team maintainers.
+	RoleApprover = "approver"
+
+	// RoleReviewer represents the role of the people that are reviewers, that is team members.
+	RoleReviewer = "reviewer"
+
+	defaultTimeout = 30 * time.Second
+
+	// waitDelay represents the maximum duration to wait for the output of the command to be closed after it is killed,
+	// in case a process it started still holds it.
+	waitDelay = time.Second
+
+	// maxOutputExcerpt represents the maximum length of the command output reported in the errors.
+	maxOutputExcerpt = 200
+)
+
+// Options represents the options to load the people from an external command.
+type Options struct {
+	// Path represents the path to the executable.
+	Path string
+
+	// Args represents the arguments of the executable.
+	Args []string
+
+	// Timeout represents the maximum duration of the command, after which it is killed.
+	Timeout time.Duration
+}
+
+// AddPFlags adds the external command options' flags to a flag set.
+func (o *Options) AddPFlags(pfs *pflag.FlagSet) {
+	pfs.StringVar(&o.Path, "command-path", "", "The path to the executable printing the people of the team. It reads the org and team context as JSON on stdin and writes a JSON list of handles and roles on stdout")
+	pfs.StringSliceVar(&o.Args, "command-args", nil, "The arguments of the executable printing the people of the team")
+	pfs.DurationVar(&o.Timeout, "command-timeout", defaultTimeout, "The maximum duration of the executable printing the people of the team")
+}
+
+// Validate validates the external command options. It possibly returns an error.
+func (o *Options) Validate() error {
+	if o.Path == "" {
+		return errors.New("command path is empty")
+	}
+
+	if o.Timeout <= 0 {
+		return errors.New("command timeout must be positive")
+	}
+
+	return nil
+}
+
+// Request represents the context written as JSON on the command's stdin.
+type Request struct {
+	Org  string `json:"org"`
+	Team string `json:"team"`
+
+	// Repository represents the name of the repository of the binding, if any.
+	Repository string `json:"repository,omitempty"`
+
+	// GitRef represents the git reference of the repository of the binding, if any.
+	GitRef string `json:"git_ref,omitempty"`
+
+	// Path represents the path scope of the binding, if any.
+	Path string `json:"path,omitempty"`
+
+	// Dir represents the path to the local checkout, if any.
+	Dir string `json:"dir,omitempty"`
+
+	// Maintainers represents the current maintainers of the team in the Peribolos config.
+	Maintainers []string `json:"maintainers"`
+
+	// Members represents the current members of the team in the Peribolos config.
+	Members []string `json:"members"`
+}
+
+// Person represents an entry of the JSON list read from the command's stdout.
+type Person struct {
+	Handle string `json:"handle"`
+
+	// Role represents either approver or reviewer. When empty, the person is an approver.
+	Role string `json:"role,omitempty"`
+}
+
+// Source represents an external command, as a people source of truth.
+type Source struct {
+	Options *Options
+
+	// Request represents the context of the binding. The current people of the team are filled when loading.
+	Request Request
+}
+
+// LoadPeople runs the command with the context of the binding and returns the people it prints.
+// It possibly returns an error.
+func (s *Source) LoadPeople(config *peribolos.FullConfig) (*syncer.People, error) {
+	request := s.Request
+	request.Maintainers, request.Members = []string{}, []string{}
+
+	if config != nil {
+		if team, err := orgs.GetTeam(config, request.Org, request.Team); err == nil {
+			request.Maintainers, request.Members = team.Maintainers, team.Members
+		}
+	}
+
+	stdin, err := json.Marshal(&request)
+	if err != nil {
+		return nil, errors.Wrap(err, "error encoding command request")
+	}
+
+	stdout, err := s.run(stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(stdout)
+}
+
+func (s *Source) run(stdin []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.Options.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, s.Options.Path, s.Options.Args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+
+	// Kill the processes started by the command too when timing out.
+	setProcessGroup(cmd)
+
+	err := cmd.Run()
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		//nolint:goerr113
+		return nil, fmt.Errorf("command %s timed out after %s", s.Options.Path, s.Options.Timeout)
+	case err != nil:
+		return nil, errors.Wrapf(err, "error running command %s: %s", s.Options.Path, excerpt(stderr.Bytes()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// Parse parses the JSON list of people printed by a command. It possibly returns an error.
+func Parse(b []byte) (*syncer.People, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+
+	var list []Person
+	if err := decoder.Decode(&list); err != nil {
+		return nil, errors.Wrapf(err, "malformed command output, expected a JSON list of handles and roles, got %q",
+			excerpt(b))
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		//nolint:goerr113
+		return nil, fmt.Errorf("malformed command output, expected only a JSON list of handles and roles, got %q",
+			excerpt(b))
+	}
+
+	approvers, reviewers := sets.NewString(), sets.NewString()
+
+	for i, p := range list {
+		if !syncer.IsValidLogin(p.Handle) {
+			//nolint:goerr113
+			return nil, fmt.Errorf("malformed command output, entry %d: invalid handle %q", i, p.Handle)
+		}
+
+		switch p.Role {
+		case RoleApprover, "":
+			approvers.Insert(prowgithub.NormLogin(p.Handle))
+		case RoleReviewer:
+			reviewers.Insert(prowgithub.NormLogin(p.Handle))
+		default:
+			//nolint:goerr113
+			return nil, fmt.Errorf("malformed command output, entry %d: unknown role %q, must be one of %v",
+				i, p.Role, []string{RoleApprover, RoleReviewer})
+		}
+	}
+
+	return &syncer.People{Approvers: approvers.List(), Reviewers: reviewers.List()}, nil
+}
+
+func excerpt(b []byte) string {
+	s := strings.TrimSpace(string(b))
+	if len(s) > maxOutputExcerpt {
+		return s[:maxOutputExcerpt] + "..."
+	}
+
+	return s
+}
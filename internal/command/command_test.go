@@ -0,0 +1,160 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/command"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+var _ = Describe("Parsing command output", func() {
+	It("should load the approvers and the reviewers", func() {
+		people, err := command.Parse([]byte(`[
+{"handle": "Alice", "role": "approver"},
+{"handle": "bob"},
+{"handle": "charlie", "role": "reviewer"}
+]`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(people.Approvers).To(Equal([]string{"alice", "bob"}))
+		Expect(people.Reviewers).To(Equal([]string{"charlie"}))
+	})
+
+	It("should error on malformed JSON", func() {
+		_, err := command.Parse([]byte(`alice bob`))
+		Expect(err).To(MatchError(ContainSubstring("malformed command output")))
+	})
+
+	It("should error on content after the list", func() {
+		_, err := command.Parse([]byte(`[{"handle": "alice"}] garbage`))
+		Expect(err).To(MatchError(ContainSubstring("expected only a JSON list")))
+	})
+
+	It("should error on unknown fields", func() {
+		_, err := command.Parse([]byte(`[{"login": "alice"}]`))
+		Expect(err).To(MatchError(ContainSubstring("malformed command output")))
+	})
+
+	It("should error on unknown roles", func() {
+		_, err := command.Parse([]byte(`[{"handle": "alice", "role": "owner"}]`))
+		Expect(err).To(MatchError(ContainSubstring(`entry 0: unknown role "owner"`)))
+	})
+
+	It("should error on invalid handles", func() {
+		_, err := command.Parse([]byte(`[{"handle": "alice"}, {"handle": "-bob"}]`))
+		Expect(err).To(MatchError(ContainSubstring(`entry 1: invalid handle "-bob"`)))
+	})
+})
+
+var _ = Describe("Running a command source", func() {
+	var (
+		err     error
+		dir     string
+		script  string
+		elapsed time.Duration
+		people  *syncer.People
+		source  *command.Source
+		config  *peribolos.FullConfig
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		script = `cat > "$1"
+echo '[{"handle": "alice"}, {"handle": "bob", "role": "reviewer"}]'
+`
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{
+			"acme": {Teams: map[string]peribolos.Team{"app": {Maintainers: []string{"alice"}}}},
+		}}
+	})
+
+	JustBeforeEach(func() {
+		path := filepath.Join(dir, "people.sh")
+		Expect(os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755)).To(Succeed())
+
+		source = &command.Source{
+			Options: &command.Options{
+				Path:    path,
+				Args:    []string{filepath.Join(dir, "request.json")},
+				Timeout: time.Second,
+			},
+			Request: command.Request{Org: "acme", Team: "app", Repository: "app"},
+		}
+		start := time.Now()
+		people, err = source.LoadPeople(config)
+		elapsed = time.Since(start)
+	})
+
+	It("should load the people printed by the command", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(people.Approvers).To(Equal([]string{"alice"}))
+		Expect(people.Reviewers).To(Equal([]string{"bob"}))
+	})
+
+	It("should pass the team context on stdin", func() {
+		b, err := os.ReadFile(filepath.Join(dir, "request.json"))
+		Expect(err).ToNot(HaveOccurred())
+
+		request := command.Request{}
+		Expect(json.Unmarshal(b, &request)).To(Succeed())
+		Expect(request.Org).To(Equal("acme"))
+		Expect(request.Team).To(Equal("app"))
+		Expect(request.Repository).To(Equal("app"))
+		Expect(request.Maintainers).To(Equal([]string{"alice"}))
+		Expect(request.Members).To(BeEmpty())
+	})
+
+	Context("when the command fails", func() {
+		BeforeEach(func() {
+			script = `echo "roster unavailable" >&2
+exit 1
+`
+		})
+
+		It("should error with its stderr", func() {
+			Expect(err).To(MatchError(ContainSubstring("roster unavailable")))
+		})
+	})
+
+	Context("when the command times out", func() {
+		BeforeEach(func() {
+			script = `exec sleep 5
+`
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError(ContainSubstring("timed out")))
+		})
+	})
+
+	Context("when a process started by the command outlives the timeout", func() {
+		BeforeEach(func() {
+			script = `sleep 5; echo '[]'
+`
+		})
+
+		It("should error without waiting for it", func() {
+			Expect(err).To(MatchError(ContainSubstring("timed out")))
+			Expect(elapsed).To(BeNumerically("<", 3*time.Second))
+		})
+	})
+})
@@ -0,0 +1,148 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package derived
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// Options represents the options to derive a GitHub Team from other Teams of the Peribolos config.
+type Options struct {
+	// Teams represents the names of the Teams to derive from, or their glob patterns.
+	Teams []string
+}
+
+// AddPFlags adds the derived team options' flags to a flag set.
+func (o *Options) AddPFlags(pfs *pflag.FlagSet) {
+	pfs.StringSliceVar(&o.Teams, "derived-teams", nil, "The names or the glob patterns (e.g. '*-maintainers') of the teams of the Peribolos config from which derive the team")
+}
+
+// Validate validates the derived team options. It possibly returns an error.
+func (o *Options) Validate() error {
+	return ValidatePatterns(o.Teams)
+}
+
+// ValidatePatterns validates the specified Team names and glob patterns. It possibly returns an error.
+func ValidatePatterns(patterns []string) error {
+	if len(patterns) == 0 {
+		return errors.New("derived teams are empty")
+	}
+
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return errors.Wrapf(err, "invalid derived team pattern %q", p)
+		}
+	}
+
+	return nil
+}
+
+// Source represents the Teams of the Peribolos config from which a Team is derived, as a people source of truth.
+// The maintainers of the Teams are approvers, their members are reviewers.
+type Source struct {
+	// Org represents the GitHub organization of the Teams.
+	Org string
+
+	// Team represents the derived Team, which is never derived from itself.
+	Team string
+
+	// Patterns represents the names or the glob patterns of the Teams to derive from.
+	Patterns []string
+}
+
+// LoadPeople returns the union of the people of the matching Teams of the Peribolos config.
+// It possibly returns an error.
+func (s *Source) LoadPeople(config *peribolos.FullConfig) (*syncer.People, error) {
+	if _, ok := config.Orgs[s.Org]; !ok {
+		//nolint:goerr113
+		return nil, fmt.Errorf("organization %s not found in peribolos config", s.Org)
+	}
+
+	// The names are resolved to the references of their Teams, that the patterns match.
+	patterns := make([]string, 0, len(s.Patterns))
+
+	for _, p := range s.Patterns {
+		if strings.ContainsAny(p, `*?[\`) {
+			patterns = append(patterns, p)
+
+			continue
+		}
+
+		ref, err := orgs.ResolveTeam(config, s.Org, p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error looking up team %s", p)
+		}
+
+		patterns = append(patterns, ref)
+	}
+
+	self, err := orgs.ResolveTeam(config, s.Org, s.Team)
+	if err != nil {
+		self = s.Team
+	}
+
+	refs, err := orgs.ListTeams(config, s.Org)
+	if err != nil {
+		return nil, err
+	}
+
+	approvers, reviewers := sets.NewString(), sets.NewString()
+	matched := 0
+
+	for _, ref := range refs {
+		if ref == self || !matches(patterns, ref) {
+			continue
+		}
+
+		matched++
+
+		team, lookupErr := orgs.GetTeam(config, s.Org, ref)
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+
+		approvers.Insert(team.Maintainers...)
+		reviewers.Insert(team.Members...)
+	}
+
+	if matched == 0 {
+		//nolint:goerr113
+		return nil, fmt.Errorf("no team of organization %s matches %v", s.Org, s.Patterns)
+	}
+
+	return &syncer.People{Approvers: approvers.List(), Reviewers: reviewers.List()}, nil
+}
+
+// matches returns whether the reference of a Team matches any of the patterns. The patterns match the references of
+// the nested Teams as paths, like parent/*.
+func matches(patterns []string, ref string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, ref); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package derived_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/derived"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+var _ = Describe("Deriving a team", func() {
+	var (
+		err    error
+		source *derived.Source
+		people *syncer.People
+		config *peribolos.FullConfig
+	)
+
+	BeforeEach(func() {
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{
+			"acme": {Teams: map[string]peribolos.Team{
+				"all-maintainers":  {Members: []string{"zoe"}},
+				"app-maintainers":  {Maintainers: []string{"alice"}, Members: []string{"bob"}},
+				"libs-maintainers": {Members: []string{"bob", "charlie"}},
+				"bots":             {Members: []string{"robot"}},
+			}},
+		}}
+		source = &derived.Source{Org: "acme", Team: "all-maintainers", Patterns: []string{"*-maintainers"}}
+	})
+
+	JustBeforeEach(func() {
+		people, err = source.LoadPeople(config)
+	})
+
+	It("should merge the people of the matching teams, except itself", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(people.Approvers).To(Equal([]string{"alice"}))
+		Expect(people.Reviewers).To(Equal([]string{"bob", "charlie"}))
+	})
+
+	Context("with a list of names", func() {
+		BeforeEach(func() {
+			source.Patterns = []string{"libs-maintainers", "bots"}
+		})
+
+		It("should merge the people of the listed teams", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(people.Approvers).To(BeEmpty())
+			Expect(people.Reviewers).To(Equal([]string{"bob", "charlie", "robot"}))
+		})
+	})
+
+	Context("with an unknown team name", func() {
+		BeforeEach(func() {
+			source.Patterns = []string{"docs-maintainers"}
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError(ContainSubstring("error looking up team docs-maintainers: team not found")))
+		})
+	})
+
+	Context("with nested teams", func() {
+		BeforeEach(func() {
+			platform := peribolos.Team{Maintainers: []string{"dave"}, Children: map[string]peribolos.Team{
+				"infra-maintainers": {Members: []string{"erin"}},
+				"oncall":            {Members: []string{"frank"}},
+			}}
+			config.Orgs["acme"].Teams["platform"] = platform
+			source.Patterns = []string{"platform/*", "oncall"}
+		})
+
+		It("should match their references and look them up by name", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(people.Approvers).To(BeEmpty())
+			Expect(people.Reviewers).To(Equal([]string{"erin", "frank"}))
+		})
+	})
+
+	Context("with a pattern matching no team", func() {
+		BeforeEach(func() {
+			source.Patterns = []string{"*-reviewers"}
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError(ContainSubstring("no team of organization acme matches")))
+		})
+	})
+})
+
+var _ = Describe("Validating derived teams", func() {
+	It("should error on invalid patterns", func() {
+		Expect(derived.ValidatePatterns([]string{"[app"})).To(HaveOccurred())
+	})
+
+	It("should error without teams", func() {
+		Expect(derived.ValidatePatterns(nil)).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,291 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FuncOwners represents the operand selecting both the approvers and the reviewers of an Owners hierarchy.
+	FuncOwners = "owners"
+
+	// FuncApprovers represents the operand selecting the approvers of an Owners hierarchy.
+	FuncApprovers = "approvers"
+
+	// FuncReviewers represents the operand selecting the reviewers of an Owners hierarchy.
+	FuncReviewers = "reviewers"
+
+	// FuncRoster represents the operand selecting the people of a roster file.
+	FuncRoster = "roster"
+
+	// FuncTeam represents the operand selecting the maintainers and the members of a Team of the Peribolos config.
+	FuncTeam = "team"
+
+	opUnion        = '|'
+	opIntersection = '&'
+	opDifference   = '-'
+)
+
+// Funcs represents the supported operand functions.
+var Funcs = []string{FuncOwners, FuncApprovers, FuncReviewers, FuncRoster, FuncTeam}
+
+// Node represents a node of a set expression: either an Operand or an Operation.
+type Node interface {
+	String() string
+}
+
+// Operand represents a set of people loaded from a source of truth.
+type Operand struct {
+	// Func represents the kind of source of truth, one of Funcs.
+	Func string
+
+	// Repository represents the repository of the source of truth, or the directory of its local checkout.
+	// For team operands, it represents the name of the Team.
+	Repository string
+
+	// GitRef represents the git reference at which load the source of truth, if any.
+	GitRef string
+
+	// Path represents the path scope of the Owners hierarchy, or the path to the roster file, if any.
+	Path string
+}
+
+func (o *Operand) String() string {
+	ref := o.Repository
+	if o.GitRef != "" {
+		ref += "@" + o.GitRef
+	}
+
+	if o.Path != "" {
+		ref += ":" + o.Path
+	}
+
+	return fmt.Sprintf("%s(%s)", o.Func, ref)
+}
+
+// Operation represents a set operation between two nodes.
+type Operation struct {
+	// Op represents the operator: | for union, & for intersection, - for difference.
+	Op rune
+
+	Left  Node
+	Right Node
+}
+
+func (o *Operation) String() string {
+	return fmt.Sprintf("(%s %c %s)", o.Left, o.Op, o.Right)
+}
+
+// Parse parses a set expression, like "approvers(app) | approvers(libs@main:pkg) - team(bots)".
+// The intersection binds tighter than the union and the difference, which are left associative.
+// It possibly returns an error.
+func Parse(s string) (Node, error) {
+	p := &parser{input: []rune(s)}
+
+	node, err := p.parseExpression()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing expression %q", s)
+	}
+
+	if p.skipSpaces(); p.pos < len(p.input) {
+		//nolint:goerr113
+		return nil, fmt.Errorf("error parsing expression %q: unexpected %q at position %d", s, p.input[p.pos], p.pos)
+	}
+
+	return node, nil
+}
+
+// Operands returns the operands of the expression, from left to right.
+func Operands(node Node) []*Operand {
+	switch n := node.(type) {
+	case *Operand:
+		return []*Operand{n}
+	case *Operation:
+		return append(Operands(n.Left), Operands(n.Right)...)
+	default:
+		return nil
+	}
+}
+
+type parser struct {
+	input []rune
+	pos   int
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space rune, or 0 at the end of the input.
+func (p *parser) peek() rune {
+	p.skipSpaces()
+
+	if p.pos >= len(p.input) {
+		return 0
+	}
+
+	return p.input[p.pos]
+}
+
+// parseExpression parses the unions and the differences of intersections.
+func (p *parser) parseExpression() (Node, error) {
+	left, err := p.parseIntersection()
+	if err != nil {
+		return nil, err
+	}
+
+	for op := p.peek(); op == opUnion || op == opDifference; op = p.peek() {
+		p.pos++
+
+		right, err := p.parseIntersection()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Operation{Op: op, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// parseIntersection parses the intersections of terms.
+func (p *parser) parseIntersection() (Node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == opIntersection {
+		p.pos++
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Operation{Op: opIntersection, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// parseTerm parses either a parenthesized expression or an operand.
+func (p *parser) parseTerm() (Node, error) {
+	if p.peek() == '(' {
+		p.pos++
+
+		node, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() != ')' {
+			//nolint:goerr113
+			return nil, fmt.Errorf("missing closing parenthesis at position %d", p.pos)
+		}
+
+		p.pos++
+
+		return node, nil
+	}
+
+	return p.parseOperand()
+}
+
+// parseOperand parses an operand, like approvers(repo@ref:path) or team(name).
+func (p *parser) parseOperand() (Node, error) {
+	p.skipSpaces()
+
+	start := p.pos
+	for p.pos < len(p.input) && unicode.IsLetter(p.input[p.pos]) {
+		p.pos++
+	}
+
+	name := string(p.input[start:p.pos])
+	if name == "" {
+		//nolint:goerr113
+		return nil, fmt.Errorf("expected an operand at position %d", start)
+	}
+
+	if !isFunc(name) {
+		//nolint:goerr113
+		return nil, fmt.Errorf("unknown operand %q at position %d, must be one of %v", name, start, Funcs)
+	}
+
+	if p.peek() != '(' {
+		//nolint:goerr113
+		return nil, fmt.Errorf("expected ( after %s at position %d", name, p.pos)
+	}
+
+	p.pos++
+	argStart := p.pos
+
+	for p.pos < len(p.input) && p.input[p.pos] != ')' {
+		p.pos++
+	}
+
+	if p.pos >= len(p.input) {
+		//nolint:goerr113
+		return nil, fmt.Errorf("missing closing parenthesis of %s at position %d", name, argStart)
+	}
+
+	arg := strings.TrimSpace(string(p.input[argStart:p.pos]))
+	p.pos++
+
+	return newOperand(name, arg, argStart)
+}
+
+// newOperand returns the operand of the specified function and argument: a team name for team operands, otherwise a
+// repository reference, as repository[@ref][:path].
+func newOperand(name, arg string, pos int) (*Operand, error) {
+	if arg == "" {
+		//nolint:goerr113
+		return nil, fmt.Errorf("empty argument of %s at position %d", name, pos)
+	}
+
+	if name == FuncTeam {
+		return &Operand{Func: name, Repository: arg}, nil
+	}
+
+	operand := &Operand{Func: name}
+
+	ref, path, _ := strings.Cut(arg, ":")
+	operand.Repository, operand.GitRef, _ = strings.Cut(ref, "@")
+	operand.Path = path
+
+	if operand.Repository == "" {
+		//nolint:goerr113
+		return nil, fmt.Errorf("empty repository of %s at position %d", name, pos)
+	}
+
+	return operand, nil
+}
+
+func isFunc(name string) bool {
+	for _, f := range Funcs {
+		if f == name {
+			return true
+		}
+	}
+
+	return false
+}
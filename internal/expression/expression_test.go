@@ -0,0 +1,166 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/expression"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// fakeSource returns the same people whatever the config.
+type fakeSource struct {
+	people *syncer.People
+}
+
+func (s *fakeSource) LoadPeople(_ *peribolos.FullConfig) (*syncer.People, error) {
+	return s.people, nil
+}
+
+var _ = Describe("Parsing expressions", func() {
+	It("should parse the operands and their references", func() {
+		node, err := expression.Parse("approvers(app@main:pkg/api) | roster(org:teams/app.csv)")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression.Operands(node)).To(Equal([]*expression.Operand{
+			{Func: "approvers", Repository: "app", GitRef: "main", Path: "pkg/api"},
+			{Func: "roster", Repository: "org", Path: "teams/app.csv"},
+		}))
+	})
+
+	It("should bind intersections tighter than unions and differences", func() {
+		node, err := expression.Parse("owners(a) | owners(b) & team(x-y) - team(z)")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node.String()).To(Equal("((owners(a) | (owners(b) & team(x-y))) - team(z))"))
+	})
+
+	It("should honor the parentheses", func() {
+		node, err := expression.Parse("owners(a) - (team(x) | team(y))")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node.String()).To(Equal("(owners(a) - (team(x) | team(y)))"))
+	})
+
+	DescribeTable("should error on invalid expressions",
+		func(s string) {
+			_, err := expression.Parse(s)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("empty", ""),
+		Entry("unknown operand", "members(app)"),
+		Entry("missing argument", "owners()"),
+		Entry("missing operand", "owners(app) |"),
+		Entry("unbalanced parentheses", "(owners(app) | owners(libs)"),
+		Entry("trailing input", "owners(app) owners(libs)"),
+	)
+})
+
+var _ = Describe("Evaluating expressions", func() {
+	var (
+		err     error
+		s       string
+		people  *syncer.People
+		sources map[string]*syncer.People
+		config  *peribolos.FullConfig
+	)
+
+	BeforeEach(func() {
+		sources = map[string]*syncer.People{
+			"app":  {Approvers: []string{"alice", "bob"}, Reviewers: []string{"charlie"}},
+			"libs": {Approvers: []string{"dave"}, Reviewers: []string{"bob", "erin"}},
+		}
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{
+			"acme": {Teams: map[string]peribolos.Team{
+				"bots": {Members: []string{"bob"}},
+			}},
+		}}
+	})
+
+	JustBeforeEach(func() {
+		var node expression.Node
+
+		node, err = expression.Parse(s)
+		Expect(err).ToNot(HaveOccurred())
+
+		people, err = (&expression.Source{
+			Expression: node,
+			Org:        "acme",
+			NewSource: func(operand *expression.Operand) (syncer.PeopleSource, error) {
+				return &fakeSource{people: sources[operand.Repository]}, nil
+			},
+		}).LoadPeople(config)
+	})
+
+	Context("with a union of approvers minus a team", func() {
+		BeforeEach(func() {
+			s = "approvers(app) | approvers(libs) - team(bots)"
+		})
+
+		It("should merge the approvers and remove the team people", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(people.Approvers).To(Equal([]string{"alice", "dave"}))
+			Expect(people.Reviewers).To(BeEmpty())
+		})
+	})
+
+	Context("with an intersection", func() {
+		BeforeEach(func() {
+			s = "owners(app) & owners(libs)"
+		})
+
+		It("should keep the left people in the right operand, with their role", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(people.Approvers).To(Equal([]string{"bob"}))
+			Expect(people.Reviewers).To(BeEmpty())
+		})
+	})
+
+	Context("with reviewers", func() {
+		BeforeEach(func() {
+			s = "reviewers(app) | reviewers(libs)"
+		})
+
+		It("should load the reviewers only", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(people.Approvers).To(BeEmpty())
+			Expect(people.Reviewers).To(Equal([]string{"bob", "charlie", "erin"}))
+		})
+	})
+
+	Context("with handles of different casing", func() {
+		BeforeEach(func() {
+			sources["app"] = &syncer.People{Approvers: []string{"alice", "carol"}}
+			config.Orgs["acme"].Teams["infra"] = peribolos.Team{Maintainers: []string{"Alice"}, Members: []string{"Carol"}}
+			s = "owners(app) - team(infra) | team(infra) & owners(app)"
+		})
+
+		It("should compare them case-insensitively", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(people.Approvers).To(Equal([]string{"alice"}))
+			Expect(people.Reviewers).To(Equal([]string{"carol"}))
+		})
+	})
+
+	Context("with an unknown team", func() {
+		BeforeEach(func() {
+			s = "owners(app) - team(unknown)"
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError(ContainSubstring("team(unknown)")))
+		})
+	})
+})
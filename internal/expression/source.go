@@ -0,0 +1,142 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// SourceFactory returns the source of truth of a repository operand: an Owners hierarchy or a roster file.
+type SourceFactory func(operand *Operand) (syncer.PeopleSource, error)
+
+// Source represents a set expression over sources of truth and Peribolos Teams, as a people source of truth.
+type Source struct {
+	// Expression represents the parsed set expression.
+	Expression Node
+
+	// Org represents the GitHub organization of the Teams of the team operands.
+	Org string
+
+	// NewSource returns the sources of truth of the repository operands.
+	NewSource SourceFactory
+}
+
+// LoadPeople evaluates the expression and returns its people. The union merges the people role by role, while the
+// intersection and the difference keep the people of the left operand, with their role, who are respectively in or
+// not in the right operand, whatever their role. The handles are compared case-insensitively, as GitHub logins are,
+// and returned lowercased. It possibly returns an error.
+func (s *Source) LoadPeople(config *peribolos.FullConfig) (*syncer.People, error) {
+	return s.eval(s.Expression, config)
+}
+
+func (s *Source) eval(node Node, config *peribolos.FullConfig) (*syncer.People, error) {
+	switch n := node.(type) {
+	case *Operand:
+		people, err := s.load(n, config)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error loading %s", n)
+		}
+
+		return &syncer.People{Approvers: toLower(people.Approvers), Reviewers: toLower(people.Reviewers)}, nil
+	case *Operation:
+		left, err := s.eval(n.Left, config)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := s.eval(n.Right, config)
+		if err != nil {
+			return nil, err
+		}
+
+		return apply(n.Op, left, right), nil
+	default:
+		//nolint:goerr113
+		return nil, fmt.Errorf("unknown expression node %T", node)
+	}
+}
+
+func (s *Source) load(operand *Operand, config *peribolos.FullConfig) (*syncer.People, error) {
+	if operand.Func == FuncTeam {
+		team, err := orgs.GetTeam(config, s.Org, operand.Repository)
+		if err != nil {
+			return nil, err
+		}
+
+		return &syncer.People{Approvers: team.Maintainers, Reviewers: team.Members}, nil
+	}
+
+	source, err := s.NewSource(operand)
+	if err != nil {
+		return nil, err
+	}
+
+	people, err := source.LoadPeople(config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operand.Func {
+	case FuncApprovers:
+		return &syncer.People{Approvers: people.Approvers}, nil
+	case FuncReviewers:
+		return &syncer.People{Reviewers: people.Reviewers}, nil
+	default:
+		return people, nil
+	}
+}
+
+func apply(op rune, left, right *syncer.People) *syncer.People {
+	switch op {
+	case opUnion:
+		return &syncer.People{
+			Approvers: sets.NewString(left.Approvers...).Insert(right.Approvers...).List(),
+			Reviewers: sets.NewString(left.Reviewers...).Insert(right.Reviewers...).List(),
+		}
+	case opIntersection:
+		all := sets.NewString(right.All()...)
+
+		return &syncer.People{
+			Approvers: sets.NewString(left.Approvers...).Intersection(all).List(),
+			Reviewers: sets.NewString(left.Reviewers...).Intersection(all).List(),
+		}
+	default:
+		all := sets.NewString(right.All()...)
+
+		return &syncer.People{
+			Approvers: sets.NewString(left.Approvers...).Difference(all).List(),
+			Reviewers: sets.NewString(left.Reviewers...).Difference(all).List(),
+		}
+	}
+}
+
+// toLower returns the lowercased handles.
+func toLower(handles []string) []string {
+	lowered := make([]string, 0, len(handles))
+
+	for _, h := range handles {
+		lowered = append(lowered, strings.ToLower(h))
+	}
+
+	return lowered
+}
@@ -15,24 +15,27 @@
 package git
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
 	gitplumbing "github.com/go-git/go-git/v5/plumbing"
 	gitobject "github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/google/uuid"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/pkg/errors"
 )
 
-func NewEphemeralGitBranch(repo *git.Repository, worktree *git.Worktree) (string, error) {
-	id := uuid.New()
-
-	refName := id.String()
+// UpstreamRemoteName is the name of the git remote of the forked repository.
+const UpstreamRemoteName = "upstream"
 
+// NewGitBranch creates a branch with the specified name from the repository HEAD and checks it out.
+// An existing branch with the same name is replaced.
+func NewGitBranch(repo *git.Repository, worktree *git.Worktree, refName string) error {
 	headRef, err := repo.Head()
 	if err != nil {
-		return "", errors.Wrap(err, "error getting repository HEAD reference")
+		return errors.Wrap(err, "error getting repository HEAD reference")
 	}
 
 	ref := gitplumbing.NewHashReference(
@@ -40,16 +43,71 @@ func NewEphemeralGitBranch(repo *git.Repository, worktree *git.Worktree) (string
 		headRef.Hash(),
 	)
 	if err = repo.Storer.SetReference(ref); err != nil {
-		return "", errors.Wrap(err, "error setting head git reference")
+		return errors.Wrap(err, "error setting head git reference")
 	}
 
 	if err = worktree.Checkout(&git.CheckoutOptions{
 		Branch: gitplumbing.NewBranchReferenceName(refName),
 	}); err != nil {
-		return "", errors.Wrap(err, "error checking out just created branch")
+		return errors.Wrap(err, "error checking out just created branch")
+	}
+
+	return nil
+}
+
+// CheckoutUpstreamBranch adds the upstream remote with the specified URL, fetches the specified branch from it and
+// checks out its latest commit, so that new branches start from the current upstream content.
+// It possibly returns an error.
+func CheckoutUpstreamBranch(repo *git.Repository, worktree *git.Worktree, url, branch string,
+	auth transport.AuthMethod,
+) error {
+	remote, err := repo.CreateRemote(&gitconfig.RemoteConfig{
+		Name: UpstreamRemoteName,
+		URLs: []string{url},
+	})
+	if err != nil {
+		return errors.Wrap(err, "error creating upstream git remote")
 	}
 
-	return refName, nil
+	refName := gitplumbing.NewRemoteReferenceName(UpstreamRemoteName, branch)
+
+	if err = remote.Fetch(&git.FetchOptions{
+		RefSpecs: []gitconfig.RefSpec{
+			gitconfig.RefSpec(fmt.Sprintf("+%s:%s", gitplumbing.NewBranchReferenceName(branch), refName)),
+		},
+		Auth: auth,
+	}); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return errors.Wrapf(err, "error fetching upstream git branch %s", branch)
+	}
+
+	ref, err := repo.Reference(refName, true)
+	if err != nil {
+		return errors.Wrapf(err, "error getting upstream git branch %s reference", branch)
+	}
+
+	if err = worktree.Checkout(&git.CheckoutOptions{
+		Hash:  ref.Hash(),
+		Force: true,
+	}); err != nil {
+		return errors.Wrapf(err, "error checking out upstream git branch %s", branch)
+	}
+
+	return nil
+}
+
+// ForcePushBranch pushes the specified branch to the origin remote, overwriting the remote branch if it exists.
+func ForcePushBranch(repo *git.Repository, refName string, auth transport.AuthMethod) error {
+	ref := gitplumbing.NewBranchReferenceName(refName)
+
+	if err := repo.Push(&git.PushOptions{
+		RefSpecs: []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("+%s:%s", ref, ref))},
+		Auth:     auth,
+		Force:    true,
+	}); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return errors.Wrap(err, "error force pushing git branch")
+	}
+
+	return nil
 }
 
 func StageAndCommit(repo *git.Repository, worktree *git.Worktree, author *gitobject.Signature,
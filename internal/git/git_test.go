@@ -0,0 +1,117 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitplumbing "github.com/go-git/go-git/v5/plumbing"
+	gitobject "github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	syncergit "github.com/falcosecurity/peribolos-syncer/internal/git"
+)
+
+func commitFile(worktree *git.Worktree, root, content string) gitplumbing.Hash {
+	Expect(os.WriteFile(filepath.Join(root, "org.yaml"), []byte(content), 0o600)).To(Succeed())
+
+	_, err := worktree.Add("org.yaml")
+	Expect(err).To(Succeed())
+
+	hash, err := worktree.Commit("update org.yaml", &git.CommitOptions{
+		Author: &gitobject.Signature{Name: "bot", Email: "bot@acme.org", When: time.Now()},
+	})
+	Expect(err).To(Succeed())
+
+	return hash
+}
+
+var _ = Describe("Checking out the upstream branch", func() {
+	var (
+		err            error
+		upstreamPath   string
+		upstreamHead   gitplumbing.Hash
+		clonePath      string
+		clone          *git.Repository
+		cloneWorktree  *git.Worktree
+		upstreamBranch string
+	)
+
+	BeforeEach(func() {
+		upstreamBranch = "main"
+
+		// Create the upstream repository.
+		upstreamPath = GinkgoT().TempDir()
+		upstream, err := git.PlainInit(upstreamPath, false)
+		Expect(err).To(Succeed())
+		Expect(upstream.Storer.SetReference(gitplumbing.NewSymbolicReference(gitplumbing.HEAD,
+			gitplumbing.NewBranchReferenceName(upstreamBranch)))).To(Succeed())
+
+		upstreamWorktree, err := upstream.Worktree()
+		Expect(err).To(Succeed())
+		commitFile(upstreamWorktree, upstreamPath, "orgs: {}\n")
+
+		// Fork the upstream repository before it moves forward.
+		forkPath := GinkgoT().TempDir()
+		_, err = git.PlainClone(forkPath, true, &git.CloneOptions{URL: upstreamPath})
+		Expect(err).To(Succeed())
+
+		upstreamHead = commitFile(upstreamWorktree, upstreamPath, "orgs:\n  acme: {}\n")
+
+		// Clone the stale fork.
+		clonePath = GinkgoT().TempDir()
+		clone, err = git.PlainClone(clonePath, false, &git.CloneOptions{URL: forkPath})
+		Expect(err).To(Succeed())
+
+		cloneWorktree, err = clone.Worktree()
+		Expect(err).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		err = syncergit.CheckoutUpstreamBranch(clone, cloneWorktree, upstreamPath, upstreamBranch, nil)
+	})
+
+	It("should check out the upstream content", func() {
+		Expect(err).To(Succeed())
+
+		head, err := clone.Head()
+		Expect(err).To(Succeed())
+		Expect(head.Hash()).To(Equal(upstreamHead))
+		Expect(os.ReadFile(filepath.Join(clonePath, "org.yaml"))).To(Equal([]byte("orgs:\n  acme: {}\n")))
+	})
+
+	It("should branch from the upstream content", func() {
+		Expect(err).To(Succeed())
+		Expect(syncergit.NewGitBranch(clone, cloneWorktree, "sync")).To(Succeed())
+
+		ref, err := clone.Reference(gitplumbing.NewBranchReferenceName("sync"), true)
+		Expect(err).To(Succeed())
+		Expect(ref.Hash()).To(Equal(upstreamHead))
+	})
+
+	Context("when the upstream branch does not exist", func() {
+		BeforeEach(func() {
+			upstreamBranch = "unknown"
+		})
+
+		It("should fail", func() {
+			Expect(err).ToNot(Succeed())
+		})
+	})
+})
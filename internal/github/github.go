@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"strings"
+	"unicode"
 
 	"github.com/go-git/go-git/v5"
 	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
@@ -28,6 +30,8 @@ import (
 	prowflags "k8s.io/test-infra/prow/flagutil"
 	gitv2 "k8s.io/test-infra/prow/git/v2"
 	prowgithub "k8s.io/test-infra/prow/github"
+
+	syncergit "github.com/falcosecurity/peribolos-syncer/internal/git"
 )
 
 // GitHubOptions represents options to interact with GitHub.
@@ -45,6 +49,11 @@ func (o *GitHubOptions) AddPFlags(pfs *pflag.FlagSet) {
 	pfs.BoolVar(&o.DryRun, "dry-run", false, "Dry run for testing. Uses API tokens but does not mutate.")
 	pfs.StringVar(&o.Username, "github-username", "", "The GitHub username")
 
+	o.AddClientPFlags(pfs)
+}
+
+// AddClientPFlags adds to a flag set only the flags needed to build a GitHub client.
+func (o *GitHubOptions) AddClientPFlags(pfs *pflag.FlagSet) {
 	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
 	for _, group := range []flagutil.OptionGroup{
 		o,
@@ -75,7 +84,12 @@ func (o *GitHubOptions) GetGitClientFactory() (gitv2.ClientFactory, error) {
 	return factory, nil
 }
 
-func (o *GitHubOptions) ForkRepository(githubClient prowgithub.Client, githubOrg, githubRepo, token string) (*git.Repository, *git.Worktree, string, error) {
+// ForkRepository ensures the user's fork of the specified repository and clones it on a temporary directory, with the
+// specified upstream git reference checked out, so that changes are based on the current upstream content.
+// It returns the repository, its worktree and the local path. It possibly returns an error.
+func (o *GitHubOptions) ForkRepository(githubClient prowgithub.Client, githubOrg, githubRepo, baseRef,
+	token string,
+) (*git.Repository, *git.Worktree, string, error) {
 	githubClient.Used()
 
 	path, err := os.MkdirTemp("", "orgs")
@@ -93,11 +107,18 @@ func (o *GitHubOptions) ForkRepository(githubClient prowgithub.Client, githubOrg
 		return nil, nil, "", errors.Wrap(err, "error generating orgs config repository URL")
 	}
 
+	upstreamRepoURL, err := url.JoinPath(fmt.Sprintf("https://%s", o.Host), githubOrg, githubRepo)
+	if err != nil {
+		return nil, nil, "", errors.Wrap(err, "error generating upstream orgs config repository URL")
+	}
+
+	auth := &githttp.BasicAuth{
+		Username: o.Username,
+		Password: token,
+	}
+
 	repository, err := git.PlainClone(path, false, &git.CloneOptions{
-		Auth: &githttp.BasicAuth{
-			Username: o.Username,
-			Password: token,
-		},
+		Auth:     auth,
 		URL:      configRepoURL,
 		Progress: nil,
 	})
@@ -110,5 +131,48 @@ func (o *GitHubOptions) ForkRepository(githubClient prowgithub.Client, githubOrg
 		return nil, nil, "", errors.Wrap(err, "error getting repository worktree")
 	}
 
+	// The fork may be behind upstream: start from the upstream base reference.
+	if err = syncergit.CheckoutUpstreamBranch(repository, worktree, upstreamRepoURL, baseRef, auth); err != nil {
+		return nil, nil, "", errors.Wrap(err, "error syncing the fork with upstream")
+	}
+
 	return repository, worktree, path, nil
 }
+
+// NewClientFromTokenFile returns a GitHub client authenticated with the token read from the token file, and the token.
+// It possibly returns an error.
+func (o *GitHubOptions) NewClientFromTokenFile() (prowgithub.Client, string, error) {
+	token, err := GetTokenFromFile(o.TokenPath)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "error reading token from file")
+	}
+
+	client, err := o.GitHubClientWithAccessToken(token)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "error generating github client with specified access token")
+	}
+
+	return client, token, nil
+}
+
+// GetTokenFromFile returns the token read from the specified file, without non printable characters.
+// It possibly returns an error.
+func GetTokenFromFile(path string) (string, error) {
+	token, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "error reading token file")
+	}
+
+	return removeNonPrintableChars(string(token)), nil
+}
+
+func removeNonPrintableChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsPrint(r):
+			return r
+		default:
+			return -1
+		}
+	}, s)
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	prowgithub "k8s.io/test-infra/prow/github"
+)
+
+// PullRequest represents a Pull Request to be opened from a branch of the user's fork.
+type PullRequest struct {
+	Title string
+	Body  string
+
+	// Branch represents the name of the fork's branch that holds the changes.
+	Branch string
+
+	// BaseRef represents the git reference on which the changes should be merged.
+	BaseRef string
+
+	// Marker represents a string contained in the body of every Pull Request for the same changes.
+	Marker string
+}
+
+// pullRequestClient represents the GitHub client operations needed to manage Pull Requests.
+type pullRequestClient interface {
+	GetPullRequests(org, repo string) ([]prowgithub.PullRequest, error)
+	CreatePullRequest(org, repo, title, body, head, base string, canModify bool) (int, error)
+	UpdatePullRequest(org, repo string, number int, title, body *string, open *bool, branch *string, canModify *bool) error
+	CreateComment(org, repo string, number int, comment string) error
+	ClosePR(org, repo string, number int) error
+}
+
+// EnsurePullRequest opens the specified Pull Request, or updates the open one previously authored by the user for the
+// same branch. The other open Pull Requests previously authored by the user for the same changes are closed.
+// It returns the Pull Request number and whether it already existed. It possibly returns an error.
+func (o *GitHubOptions) EnsurePullRequest(client pullRequestClient, org, repo string, pr *PullRequest) (int, bool, error) {
+	prs, err := client.GetPullRequests(org, repo)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "error listing open pull requests")
+	}
+
+	number := 0
+	duplicates := []int{}
+
+	for i := range prs {
+		if !o.isSamePullRequest(&prs[i], pr) {
+			continue
+		}
+
+		if number == 0 && prs[i].Head.Ref == pr.Branch && strings.EqualFold(prs[i].Head.Repo.Owner.Login, o.Username) {
+			number = prs[i].Number
+
+			continue
+		}
+
+		duplicates = append(duplicates, prs[i].Number)
+	}
+
+	existing := number != 0
+
+	if existing {
+		if err = client.UpdatePullRequest(org, repo, number, &pr.Title, &pr.Body, nil, nil, nil); err != nil {
+			return 0, false, errors.Wrap(err, "error updating github pull request")
+		}
+	} else {
+		number, err = client.CreatePullRequest(org, repo, pr.Title, pr.Body,
+			fmt.Sprintf("%s:%s", o.Username, pr.Branch), pr.BaseRef, false)
+		if err != nil {
+			return 0, false, errors.Wrap(err, "error creating github pull request")
+		}
+	}
+
+	for _, duplicate := range duplicates {
+		if err = client.CreateComment(org, repo, duplicate, fmt.Sprintf("Superseded by #%d.", number)); err != nil {
+			return 0, false, errors.Wrapf(err, "error commenting duplicate github pull request %d", duplicate)
+		}
+
+		if err = client.ClosePR(org, repo, duplicate); err != nil {
+			return 0, false, errors.Wrapf(err, "error closing duplicate github pull request %d", duplicate)
+		}
+	}
+
+	return number, existing, nil
+}
+
+// isSamePullRequest returns whether the open Pull Request has been authored by the user for the same changes of the
+// specified one, either from the same branch or with the same marker.
+func (o *GitHubOptions) isSamePullRequest(open *prowgithub.PullRequest, pr *PullRequest) bool {
+	if !strings.EqualFold(open.User.Login, o.Username) {
+		return false
+	}
+
+	return open.Head.Ref == pr.Branch || (pr.Marker != "" && strings.Contains(open.Body, pr.Marker))
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	prowgithub "k8s.io/test-infra/prow/github"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/github"
+)
+
+type fakePullRequestClient struct {
+	prs      []prowgithub.PullRequest
+	created  []string
+	updated  []int
+	comments map[int]string
+	closed   []int
+}
+
+func (c *fakePullRequestClient) GetPullRequests(_, _ string) ([]prowgithub.PullRequest, error) {
+	return c.prs, nil
+}
+
+func (c *fakePullRequestClient) CreatePullRequest(_, _, title, _, head, base string, _ bool) (int, error) {
+	c.created = append(c.created, fmt.Sprintf("%s %s->%s", title, head, base))
+
+	return 42, nil
+}
+
+func (c *fakePullRequestClient) UpdatePullRequest(_, _ string, number int, _, _ *string, _ *bool, _ *string,
+	_ *bool,
+) error {
+	c.updated = append(c.updated, number)
+
+	return nil
+}
+
+func (c *fakePullRequestClient) CreateComment(_, _ string, number int, comment string) error {
+	c.comments[number] = comment
+
+	return nil
+}
+
+func (c *fakePullRequestClient) ClosePR(_, _ string, number int) error {
+	c.closed = append(c.closed, number)
+
+	return nil
+}
+
+// openPullRequest returns an open Pull Request authored by the specified user from a branch of their fork.
+func openPullRequest(number int, user, branch, title, body string) prowgithub.PullRequest {
+	pr := prowgithub.PullRequest{Number: number, Title: title, Body: body}
+	pr.User.Login = user
+	pr.Head.Ref = branch
+	pr.Head.Repo.Owner.Login = user
+
+	return pr
+}
+
+var _ = Describe("Ensuring a pull request", func() {
+	var (
+		err      error
+		number   int
+		existing bool
+		client   *fakePullRequestClient
+		o        *github.GitHubOptions
+		pr       *github.PullRequest
+	)
+
+	BeforeEach(func() {
+		client = &fakePullRequestClient{comments: map[int]string{}}
+		o = &github.GitHubOptions{Username: "bot"}
+		pr = &github.PullRequest{
+			Title:   "Sync team app",
+			Body:    "Body.\n<!-- marker -->",
+			Branch:  "peribolos-syncer/acme/app",
+			BaseRef: "main",
+			Marker:  "<!-- marker -->",
+		}
+	})
+
+	JustBeforeEach(func() {
+		number, existing, err = o.EnsurePullRequest(client, "acme", "org", pr)
+	})
+
+	Context("when no pull request is open", func() {
+		It("should create it", func() {
+			Expect(err).To(Succeed())
+			Expect(number).To(Equal(42))
+			Expect(existing).To(BeFalse())
+			Expect(client.created).To(Equal([]string{"Sync team app bot:peribolos-syncer/acme/app->main"}))
+		})
+	})
+
+	Context("when the pull request of the branch is open", func() {
+		BeforeEach(func() {
+			client.prs = []prowgithub.PullRequest{openPullRequest(7, "bot", pr.Branch, "Old title", "Old body.")}
+		})
+
+		It("should update it", func() {
+			Expect(err).To(Succeed())
+			Expect(number).To(Equal(7))
+			Expect(existing).To(BeTrue())
+			Expect(client.updated).To(Equal([]int{7}))
+			Expect(client.created).To(BeEmpty())
+		})
+	})
+
+	Context("when other pull requests are open for the same changes", func() {
+		BeforeEach(func() {
+			client.prs = []prowgithub.PullRequest{
+				openPullRequest(7, "bot", pr.Branch, pr.Title, pr.Body),
+				openPullRequest(8, "bot", "old-branch", "Old title", "Old body.\n<!-- marker -->"),
+			}
+		})
+
+		It("should close them as superseded", func() {
+			Expect(err).To(Succeed())
+			Expect(number).To(Equal(7))
+			Expect(client.closed).To(Equal([]int{8}))
+			Expect(client.comments).To(Equal(map[int]string{8: "Superseded by #7."}))
+		})
+	})
+
+	Context("when unrelated pull requests are open", func() {
+		BeforeEach(func() {
+			client.prs = []prowgithub.PullRequest{
+				openPullRequest(8, "bot", "other-branch", pr.Title, "Another body."),
+				openPullRequest(9, "someone", pr.Branch, pr.Title, pr.Body),
+			}
+		})
+
+		It("should leave them open", func() {
+			Expect(err).To(Succeed())
+			Expect(number).To(Equal(42))
+			Expect(existing).To(BeFalse())
+			Expect(client.closed).To(BeEmpty())
+			Expect(client.comments).To(BeEmpty())
+		})
+	})
+})
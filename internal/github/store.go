@@ -0,0 +1,194 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/go-git/go-git/v5"
+	gitobject "github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/pkg/errors"
+	prowgithub "k8s.io/test-infra/prow/github"
+
+	syncergit "github.com/falcosecurity/peribolos-syncer/internal/git"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+const modeConfigFile = 0o644
+
+// fileGetter represents the GitHub client operation needed to read a remote file.
+type fileGetter interface {
+	GetFile(org, repo, filepath, commit string) ([]byte, error)
+}
+
+// RemoteStore represents a Peribolos config read from a remote GitHub repository, without cloning it.
+// It is read-only.
+type RemoteStore struct {
+	Client fileGetter
+
+	Org     string
+	Repo    string
+	Path    string
+	BaseRef string
+}
+
+// Load returns the content of the remote Peribolos config. It possibly returns an error.
+func (s *RemoteStore) Load() ([]byte, error) {
+	b, err := s.Client.GetFile(s.Org, s.Repo, s.Path, s.BaseRef)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading the peribolos config from the repository")
+	}
+
+	return b, nil
+}
+
+// Save always returns an error, as the remote Peribolos config is read-only.
+func (s *RemoteStore) Save(_ []byte) error {
+	return errors.New("remote peribolos config is read-only")
+}
+
+// Description represents the description of the published changes.
+type Description struct {
+	// CommitMessage represents the message of the commit of the changes.
+	CommitMessage string
+
+	// Title represents the title of the Pull Request.
+	Title string
+
+	// Body represents the body of the Pull Request.
+	Body string
+}
+
+// Fork represents a Peribolos config stored on a clone of the user's fork of the config repository, based on the
+// upstream git reference. Its changes are committed to a branch of the fork and published via Pull Request.
+type Fork struct {
+	Options *GitHubOptions
+	Client  prowgithub.Client
+	Token   string
+
+	Org     string
+	Repo    string
+	Path    string
+	BaseRef string
+
+	// Branch represents the name of the fork's branch that holds the changes.
+	Branch string
+
+	// Marker represents a string contained in the body of every Pull Request for the same changes.
+	Marker string
+
+	// Author represents the author of the commit of the changes.
+	Author *gitobject.Signature
+
+	// PGPEntity represents the PGP entity signing the commit of the changes.
+	PGPEntity *openpgp.Entity
+
+	// Describe returns the description of the changes of the plan.
+	Describe func(plan *syncer.Plan) *Description
+
+	// PullRequest represents the number of the published Pull Request.
+	PullRequest int
+
+	// Existing represents whether the published Pull Request already existed.
+	Existing bool
+
+	repo     *git.Repository
+	worktree *git.Worktree
+	dir      string
+}
+
+// Load clones the fork on a new branch based on the upstream git reference, and returns the content of the
+// Peribolos config. It possibly returns an error.
+func (f *Fork) Load() ([]byte, error) {
+	repo, worktree, dir, err := f.Options.ForkRepository(f.Client, f.Org, f.Repo, f.BaseRef, f.Token)
+	if err != nil {
+		return nil, errors.Wrap(err, "error forking the config repository")
+	}
+
+	f.repo, f.worktree, f.dir = repo, worktree, dir
+
+	// Create a branch for the changes, named after the sync so that later syncs update the same pull request.
+	if err = syncergit.NewGitBranch(repo, worktree, f.Branch); err != nil {
+		return nil, errors.Wrap(err, "error creating new branch for changes on the config")
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, f.Path))
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading the peribolos config")
+	}
+
+	return b, nil
+}
+
+// Save writes the content of the Peribolos config to the working tree of the fork. It possibly returns an error.
+func (f *Fork) Save(b []byte) error {
+	if f.worktree == nil {
+		return errors.New("fork has not been loaded")
+	}
+
+	if err := os.WriteFile(filepath.Join(f.dir, f.Path), b, modeConfigFile); err != nil {
+		return errors.Wrap(err, "error writing the recompiled peribolos config")
+	}
+
+	return nil
+}
+
+// Publish commits the saved changes, then pushes the branch to the fork and opens the Pull Request, or updates the
+// one of previous syncs. Push and Pull Request are skipped when dry run. It possibly returns an error.
+func (f *Fork) Publish(plan *syncer.Plan) error {
+	if f.worktree == nil {
+		return errors.New("fork has not been loaded")
+	}
+
+	description := f.Describe(plan)
+
+	// Stage the change to the config and create a commit for it.
+	if err := syncergit.StageAndCommit(f.repo, f.worktree, f.Author, f.PGPEntity, f.Path,
+		description.CommitMessage); err != nil {
+		return errors.Wrap(err, "error committing the changes on config")
+	}
+
+	// Skip push to remote and pull request creation when dry run.
+	if f.Options.DryRun {
+		return nil
+	}
+
+	// Push the branch to the remote, replacing the changes of previous syncs.
+	if err := syncergit.ForcePushBranch(f.repo, f.Branch, &githttp.BasicAuth{
+		Username: f.Options.Username,
+		Password: f.Token,
+	}); err != nil {
+		return errors.Wrap(err, "error pushing config update git branch")
+	}
+
+	// Create a Pull Request on GitHub, or update the one of previous syncs.
+	number, existing, err := f.Options.EnsurePullRequest(f.Client, f.Org, f.Repo, &PullRequest{
+		Title:   description.Title,
+		Body:    description.Body,
+		Branch:  f.Branch,
+		BaseRef: f.BaseRef,
+		Marker:  f.Marker,
+	})
+	if err != nil {
+		return err
+	}
+
+	f.PullRequest, f.Existing = number, existing
+
+	return nil
+}
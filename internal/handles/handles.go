@@ -0,0 +1,99 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handles
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// Load loads the handle mapping from the specified file. It possibly returns an error.
+func Load(path string) (syncer.HandleMapping, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading handle mapping file")
+	}
+
+	m, err := Parse(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing handle mapping file %s", path)
+	}
+
+	return m, nil
+}
+
+// Parse parses a YAML map of handles, like old logins or aliases, to GitHub logins. Chains of renames are followed
+// to the last login. It possibly returns an error.
+func Parse(b []byte) (syncer.HandleMapping, error) {
+	raw := map[string]string{}
+	if err := yaml.UnmarshalStrict(b, &raw); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling handle mapping")
+	}
+
+	m := syncer.HandleMapping{}
+
+	for handle, login := range raw {
+		if !syncer.IsValidLogin(login) {
+			//nolint:goerr113
+			return nil, fmt.Errorf("handle %s is mapped to the invalid login %q", handle, login)
+		}
+
+		key := strings.ToLower(handle)
+		if _, ok := m[key]; ok {
+			//nolint:goerr113
+			return nil, fmt.Errorf("handle %s is mapped more than once", handle)
+		}
+
+		m[key] = strings.ToLower(login)
+	}
+
+	for handle := range m {
+		login, err := resolve(m, handle)
+		if err != nil {
+			return nil, err
+		}
+
+		m[handle] = login
+	}
+
+	return m, nil
+}
+
+// resolve follows the chain of renames of the handle to the last login. It possibly returns an error on cycles.
+func resolve(m syncer.HandleMapping, handle string) (string, error) {
+	seen := map[string]bool{handle: true}
+	login := m[handle]
+
+	for {
+		next, ok := m[login]
+		if !ok || next == login {
+			return login, nil
+		}
+
+		if seen[login] {
+			//nolint:goerr113
+			return "", fmt.Errorf("handle %s is mapped in a cycle", handle)
+		}
+
+		seen[login] = true
+		login = next
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handles_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/handles"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+var _ = Describe("Parsing a handle mapping", func() {
+	var (
+		err     error
+		content string
+		m       syncer.HandleMapping
+	)
+
+	BeforeEach(func() {
+		content = `# Renamed accounts.
+OldAlice: alice-old
+alice-old: Alice
+# Corporate aliases.
+bob.smith: bob
+`
+	})
+
+	JustBeforeEach(func() {
+		m, err = handles.Parse([]byte(content))
+	})
+
+	It("should map the handles case-insensitively, following the renames", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(m.Map("oldalice")).To(Equal("alice"))
+		Expect(m.Map("Alice-Old")).To(Equal("alice"))
+		Expect(m.Map("BOB.SMITH")).To(Equal("bob"))
+		Expect(m.Map("charlie")).To(Equal("charlie"))
+	})
+
+	It("should map the people", func() {
+		people := m.Apply(&syncer.People{Approvers: []string{"oldalice", "alice"}, Reviewers: []string{"bob.smith"}})
+		Expect(people.Approvers).To(Equal([]string{"alice"}))
+		Expect(people.Reviewers).To(Equal([]string{"bob"}))
+	})
+
+	Context("with an invalid login", func() {
+		BeforeEach(func() {
+			content = "bob.smith: bob smith\n"
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError(ContainSubstring("invalid login")))
+		})
+	})
+
+	Context("with a cycle", func() {
+		BeforeEach(func() {
+			content = "alice: bob\nbob: alice\n"
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError(ContainSubstring("cycle")))
+		})
+	})
+
+	Context("with a handle mapped twice", func() {
+		BeforeEach(func() {
+			content = "alice: bob\nAlice: charlie\n"
+		})
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
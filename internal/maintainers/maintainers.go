@@ -0,0 +1,270 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintainers
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+const (
+	defaultFile        = "MAINTAINERS.md"
+	defaultHandleField = "GitHub"
+	defaultListField   = "maintainers"
+)
+
+// The profile and mention regexps only capture the candidate logins, validated by extractHandle. A mention starts
+// the value or follows a non-word character, so that emails are not mentions.
+var (
+	profileRegexp = regexp.MustCompile(`github\.com/([a-zA-Z0-9-]+)`)
+	mentionRegexp = regexp.MustCompile(`(?:^|\W)@([a-zA-Z0-9-]+)`)
+)
+
+// Options represents the options to load the people from a maintainers file: either a Markdown table or a YAML list.
+type Options struct {
+	// File represents the path to the maintainers file in the repository. Files with .yaml or .yml extension are
+	// parsed as YAML, the others as Markdown.
+	File string
+
+	// HandleField represents the table column or the YAML field containing the GitHub handles.
+	HandleField string
+
+	// RoleField represents the table column or the YAML field containing the roles. When empty, everyone is a
+	// maintainer.
+	RoleField string
+
+	// ReviewerRoles represents the role values of the people that are reviewers rather than maintainers.
+	ReviewerRoles []string
+}
+
+// AddPFlags adds the maintainers file options' flags to a flag set.
+func (o *Options) AddPFlags(pfs *pflag.FlagSet) {
+	pfs.StringVar(&o.File, "maintainers-file", defaultFile, "The path to the maintainers file from the root of the repository, either a Markdown table or a YAML list (.yaml, .yml)")
+	pfs.StringVar(&o.HandleField, "maintainers-handle-field", defaultHandleField, "The maintainers file's table column or YAML field containing the GitHub handles")
+	pfs.StringVar(&o.RoleField, "maintainers-role-field", "", "The maintainers file's table column or YAML field containing the roles. When empty, everyone is considered a maintainer")
+	pfs.StringSliceVar(&o.ReviewerRoles, "maintainers-reviewer-roles", []string{"reviewer"}, "The role values of the maintainers file that identify reviewers rather than maintainers")
+}
+
+// Validate validates the maintainers file options. It possibly returns an error.
+func (o *Options) Validate() error {
+	if o.File == "" {
+		return errors.New("maintainers file path is empty")
+	}
+
+	if o.HandleField == "" {
+		return errors.New("maintainers handle field is empty")
+	}
+
+	return nil
+}
+
+// isYAML returns whether the maintainers file is a YAML file.
+func (o *Options) isYAML() bool {
+	ext := strings.ToLower(filepath.Ext(o.File))
+
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Parse returns the people of the content of the maintainers file: the maintainers are approvers and the people with
+// a reviewer role are reviewers. It possibly returns an error.
+func (o *Options) Parse(b []byte) (*syncer.People, error) {
+	var (
+		records []map[string]string
+		err     error
+	)
+
+	if o.isYAML() {
+		records, err = parseYAML(b)
+	} else {
+		records, err = parseMarkdown(b, o.HandleField)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	approvers, reviewers := sets.NewString(), sets.NewString()
+	reviewerRoles := sets.NewString()
+
+	for _, r := range o.ReviewerRoles {
+		reviewerRoles.Insert(strings.ToLower(strings.TrimSpace(r)))
+	}
+
+	for i, record := range records {
+		value, ok := lookup(record, o.HandleField)
+		if !ok {
+			//nolint:goerr113
+			return nil, fmt.Errorf("maintainer %d has no %s field", i+1, o.HandleField)
+		}
+
+		handle, ok := extractHandle(value)
+		if !ok {
+			//nolint:goerr113
+			return nil, fmt.Errorf("maintainer %d has an invalid GitHub handle %q", i+1, value)
+		}
+
+		role, _ := lookup(record, o.RoleField)
+		if o.RoleField != "" && reviewerRoles.Has(strings.ToLower(strings.TrimSpace(role))) {
+			reviewers.Insert(handle)
+		} else {
+			approvers.Insert(handle)
+		}
+	}
+
+	return &syncer.People{Approvers: approvers.List(), Reviewers: reviewers.List()}, nil
+}
+
+// lookup returns the value of the record's field, matched case-insensitively.
+func lookup(record map[string]string, field string) (string, bool) {
+	for k, v := range record {
+		if strings.EqualFold(strings.TrimSpace(k), field) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// extractHandle returns the GitHub handle of a field value, either a GitHub profile URL, a @mention or a bare login.
+func extractHandle(value string) (string, bool) {
+	login := strings.TrimSpace(value)
+
+	if m := profileRegexp.FindStringSubmatch(value); m != nil {
+		login = m[1]
+	} else if m := mentionRegexp.FindStringSubmatch(value); m != nil {
+		login = m[1]
+	}
+
+	if !syncer.IsValidLogin(login) {
+		return "", false
+	}
+
+	return strings.ToLower(login), true
+}
+
+// parseYAML returns the records of a YAML maintainers file: either a top-level list or a list under the maintainers
+// field.
+func parseYAML(b []byte) ([]map[string]string, error) {
+	var list []map[string]interface{}
+
+	if err := yaml.Unmarshal(b, &list); err != nil {
+		wrapper := map[string][]map[string]interface{}{}
+		if err := yaml.Unmarshal(b, &wrapper); err != nil {
+			return nil, errors.Wrap(err, "error unmarshaling maintainers file")
+		}
+
+		var ok bool
+		if list, ok = wrapper[defaultListField]; !ok {
+			//nolint:goerr113
+			return nil, fmt.Errorf("maintainers file has no %s list", defaultListField)
+		}
+	}
+
+	records := make([]map[string]string, 0, len(list))
+
+	for _, item := range list {
+		record := map[string]string{}
+		for k, v := range item {
+			record[k] = fmt.Sprint(v)
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// parseMarkdown returns the rows of the first Markdown table of the maintainers file having the handle column.
+func parseMarkdown(b []byte, handleField string) ([]map[string]string, error) {
+	var (
+		header  []string
+		records []map[string]string
+	)
+
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+
+		if !strings.HasPrefix(line, "|") {
+			// The table of the maintainers ended.
+			if records != nil {
+				break
+			}
+
+			header = nil
+
+			continue
+		}
+
+		cells := splitRow(line)
+
+		switch {
+		case header == nil:
+			header = cells
+		case isSeparator(cells):
+			if _, ok := lookup(toRecord(header, header), handleField); ok {
+				records = []map[string]string{}
+			}
+		case records != nil:
+			records = append(records, toRecord(header, cells))
+		}
+	}
+
+	if records == nil {
+		//nolint:goerr113
+		return nil, fmt.Errorf("maintainers file has no table with a %s column", handleField)
+	}
+
+	return records, nil
+}
+
+func splitRow(line string) []string {
+	cells := strings.Split(strings.Trim(line, "|"), "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+
+	return cells
+}
+
+func isSeparator(cells []string) bool {
+	for _, c := range cells {
+		if strings.Trim(c, ":-") != "" || c == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func toRecord(header, cells []string) map[string]string {
+	record := map[string]string{}
+
+	for i, h := range header {
+		if i < len(cells) {
+			record[h] = cells[i]
+		}
+	}
+
+	return record
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintainers_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/maintainers"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+var _ = Describe("Parsing maintainers files", func() {
+	var (
+		err     error
+		content string
+		options *maintainers.Options
+		people  *syncer.People
+	)
+
+	BeforeEach(func() {
+		options = &maintainers.Options{
+			File:          "MAINTAINERS.md",
+			HandleField:   "GitHub",
+			ReviewerRoles: []string{"reviewer"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		people, err = options.Parse([]byte(content))
+	})
+
+	Context("with a Markdown table", func() {
+		BeforeEach(func() {
+			content = `# Maintainers
+
+| Name | Company |
+|------|---------|
+| Nobody | Acme |
+
+| Name    | GitHub                                   | Role       |
+|:--------|:-----------------------------------------|:-----------|
+| Alice   | [@Alice](https://github.com/Alice)       | Maintainer |
+| Bob     | @bob                                     | Reviewer   |
+| Charlie | charlie                                  | maintainer |
+
+Emeritus maintainers are not listed.
+`
+		})
+
+		It("should consider everyone a maintainer", func() {
+			Expect(err).To(Succeed())
+			Expect(people.Approvers).To(Equal([]string{"alice", "bob", "charlie"}))
+			Expect(people.Reviewers).To(BeEmpty())
+		})
+
+		Context("with a role column", func() {
+			BeforeEach(func() {
+				options.RoleField = "role"
+			})
+
+			It("should map the roles", func() {
+				Expect(err).To(Succeed())
+				Expect(people.Approvers).To(Equal([]string{"alice", "charlie"}))
+				Expect(people.Reviewers).To(Equal([]string{"bob"}))
+			})
+		})
+
+		Context("without the handle column", func() {
+			BeforeEach(func() {
+				options.HandleField = "Handle"
+			})
+
+			It("should error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("with an email instead of a handle", func() {
+			BeforeEach(func() {
+				content = "| Name | GitHub |\n|---|---|\n| Alice | @alice |\n| Bob | bob@corp.com |\n"
+			})
+
+			It("should error with the maintainer position", func() {
+				Expect(err).To(MatchError(`maintainer 2 has an invalid GitHub handle "bob@corp.com"`))
+			})
+		})
+
+		Context("with a handle that is not a valid GitHub login", func() {
+			BeforeEach(func() {
+				content = "| Name | GitHub |\n|---|---|\n| Bob | [@bob--smith](https://github.com/bob--smith) |\n"
+			})
+
+			It("should error with the maintainer position", func() {
+				Expect(err).To(MatchError(ContainSubstring("maintainer 1 has an invalid GitHub handle")))
+			})
+		})
+	})
+
+	Context("with a YAML list", func() {
+		BeforeEach(func() {
+			options.File = "maintainers.yaml"
+			options.HandleField = "github_id"
+			options.RoleField = "role"
+			content = `maintainers:
+- name: Alice
+  github_id: alice
+  role: maintainer
+- name: Bob
+  github_id: bob
+  role: reviewer
+`
+		})
+
+		It("should map the fields", func() {
+			Expect(err).To(Succeed())
+			Expect(people.Approvers).To(Equal([]string{"alice"}))
+			Expect(people.Reviewers).To(Equal([]string{"bob"}))
+		})
+
+		Context("at the top level", func() {
+			BeforeEach(func() {
+				content = "- github_id: alice\n"
+			})
+
+			It("should map the fields", func() {
+				Expect(err).To(Succeed())
+				Expect(people.Approvers).To(Equal([]string{"alice"}))
+			})
+		})
+
+		Context("with an invalid handle", func() {
+			BeforeEach(func() {
+				content = "- github_id: alice\n- github_id: not a login\n"
+			})
+
+			It("should error with the maintainer position", func() {
+				Expect(err).To(MatchError(ContainSubstring("maintainer 2")))
+			})
+		})
+	})
+})
@@ -0,0 +1,79 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintainers
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// fileGetter represents the GitHub client operation needed to read a remote file.
+type fileGetter interface {
+	GetFile(org, repo, filepath, commit string) ([]byte, error)
+}
+
+// LocalSource represents the maintainers file of a local checkout of a git repository, as a people source of truth.
+type LocalSource struct {
+	// Dir represents the path to the local checkout.
+	Dir string
+
+	Options *Options
+}
+
+// LoadPeople returns the people of the local maintainers file. It possibly returns an error.
+func (s *LocalSource) LoadPeople(_ *peribolos.FullConfig) (*syncer.People, error) {
+	b, err := os.ReadFile(filepath.Join(s.Dir, s.Options.File))
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading maintainers file")
+	}
+
+	people, err := s.Options.Parse(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing maintainers file")
+	}
+
+	return people, nil
+}
+
+// RemoteSource represents the maintainers file of a remote GitHub repository, as a people source of truth.
+type RemoteSource struct {
+	Client fileGetter
+
+	Org    string
+	Repo   string
+	GitRef string
+
+	Options *Options
+}
+
+// LoadPeople returns the people of the remote maintainers file. It possibly returns an error.
+func (s *RemoteSource) LoadPeople(_ *peribolos.FullConfig) (*syncer.People, error) {
+	b, err := s.Client.GetFile(s.Org, s.Repo, s.Options.File, s.GitRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading maintainers file from repository %s", s.Repo)
+	}
+
+	people, err := s.Options.Parse(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing maintainers file")
+	}
+
+	return people, nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/derived"
+	"github.com/falcosecurity/peribolos-syncer/internal/expression"
+	"github.com/falcosecurity/peribolos-syncer/internal/owners"
+	"github.com/falcosecurity/peribolos-syncer/internal/source"
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+const (
+	// RolesAll represents the role filter selecting both approvers and reviewers.
+	RolesAll = "all"
+
+	// RolesApprovers represents the role filter selecting the approvers only.
+	RolesApprovers = "approvers"
+
+	// RolesReviewers represents the role filter selecting the reviewers only.
+	RolesReviewers = "reviewers"
+
+	defaultName = "manifest"
+)
+
+// Manifest represents a list of bindings between GitHub Teams and their source of truth, to be synchronized at once.
+type Manifest struct {
+	// Name represents the name of the manifest, identifying the changes it produces.
+	Name string `json:"name,omitempty"`
+
+	// Bindings represents the GitHub Teams to synchronize, with their source of truth.
+	Bindings []Binding `json:"bindings"`
+}
+
+// Binding represents a binding between a GitHub Team and its source of truth.
+type Binding struct {
+	// Team represents the name of the GitHub Team.
+	Team string `json:"team"`
+
+	// Source represents the source of truth of the GitHub Team people.
+	Source Source `json:"source"`
+
+	// Roles represents the role filter to apply to the source people: one of all, approvers or reviewers.
+	Roles string `json:"roles,omitempty"`
+
+	// MapRoles represents the option to map approvers to Team maintainers and reviewers to Team members.
+	// When not set, the command option applies.
+	MapRoles *bool `json:"map_roles,omitempty"`
+
+	// Deny represents the patterns of the handles never added to the GitHub Team, besides the command option ones.
+	Deny []string `json:"deny,omitempty"`
+
+	// Protect represents the patterns of the handles never removed from the GitHub Team, besides the command option
+	// ones.
+	Protect []string `json:"protect,omitempty"`
+}
+
+// Source represents a source of truth in a repository.
+type Source struct {
+	// Type represents the type of the source of truth. When not set, the command option applies.
+	Type string `json:"type,omitempty"`
+
+	// Repository represents the name of the git repository containing the Owners config. It is not needed by
+	// expression and derived sources.
+	Repository string `json:"repository,omitempty"`
+
+	// GitRef represents the git reference at which load the Owners config.
+	GitRef string `json:"git_ref,omitempty"`
+
+	// Path represents the path from the root of the repository until which the roles are considered.
+	Path string `json:"path,omitempty"`
+
+	// Expression represents the set expression of expression sources. When not set, the command option applies.
+	Expression string `json:"expression,omitempty"`
+
+	// Teams represents the names or the glob patterns of the Teams of derived sources. When not set, the command
+	// option applies.
+	Teams []string `json:"teams,omitempty"`
+}
+
+// Load loads a Manifest from the specified file path. It possibly returns an error.
+func Load(path string) (*Manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading manifest file")
+	}
+
+	m := &Manifest{}
+	if err = yaml.UnmarshalStrict(b, m); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling manifest")
+	}
+
+	if err = m.Validate(); err != nil {
+		return nil, errors.Wrap(err, "error validating manifest")
+	}
+
+	if m.Name == "" {
+		m.Name = defaultName
+	}
+
+	return m, nil
+}
+
+// Validate validates the Manifest. It possibly returns an error.
+func (m *Manifest) Validate() error {
+	if len(m.Bindings) == 0 {
+		return errors.New("manifest has no bindings")
+	}
+
+	teams := map[string]bool{}
+
+	for i, b := range m.Bindings {
+		if b.Team == "" {
+			//nolint:goerr113
+			return fmt.Errorf("binding %d has an empty team name", i)
+		}
+
+		if teams[b.Team] {
+			//nolint:goerr113
+			return fmt.Errorf("team %s is bound more than once", b.Team)
+		}
+
+		teams[b.Team] = true
+
+		if b.Source.Repository == "" && b.Source.Type != source.TypeExpression && b.Source.Type != source.TypeDerived {
+			//nolint:goerr113
+			return fmt.Errorf("binding of team %s has an empty source repository", b.Team)
+		}
+
+		if len(b.Source.Teams) > 0 {
+			if err := derived.ValidatePatterns(b.Source.Teams); err != nil {
+				return errors.Wrapf(err, "binding of team %s", b.Team)
+			}
+		}
+
+		if b.Source.Expression != "" {
+			if _, err := expression.Parse(b.Source.Expression); err != nil {
+				return errors.Wrapf(err, "binding of team %s", b.Team)
+			}
+		}
+
+		if b.Source.Type != "" {
+			if err := source.ValidateType(b.Source.Type); err != nil {
+				return errors.Wrapf(err, "binding of team %s", b.Team)
+			}
+		}
+
+		if err := (&orgs.Policy{Deny: b.Deny, Protect: b.Protect}).Validate(); err != nil {
+			return errors.Wrapf(err, "binding of team %s", b.Team)
+		}
+
+		switch b.Roles {
+		case "", RolesAll, RolesApprovers, RolesReviewers:
+		default:
+			//nolint:goerr113
+			return fmt.Errorf("binding of team %s has an unknown roles filter %q", b.Team, b.Roles)
+		}
+	}
+
+	return nil
+}
+
+// OwnersLoadingOptions returns the Owners loading options of the Binding's source and role filter.
+func (b *Binding) OwnersLoadingOptions() *owners.OwnersLoadingOptions {
+	gitRef := b.Source.GitRef
+	if gitRef == "" {
+		gitRef = owners.DefaultGitRef
+	}
+
+	return &owners.OwnersLoadingOptions{
+		RepositoryName: b.Source.Repository,
+		GitRef:         gitRef,
+		ConfigPath:     b.Source.Path,
+		ApproversOnly:  b.Roles == RolesApprovers,
+		ReviewersOnly:  b.Roles == RolesReviewers,
+	}
+}
+
+// SourceOptionsOr returns the source of truth options of the Binding, overriding the type, the expression and the
+// derived Teams of the specified defaults when set.
+func (b *Binding) SourceOptionsOr(defaults *source.Options) *source.Options {
+	return defaults.WithType(b.Source.Type).WithExpression(b.Source.Expression).WithDerivedTeams(b.Source.Teams)
+}
+
+// MapRolesOr returns whether the Binding maps the roles, falling back to the specified default when not set.
+func (b *Binding) MapRolesOr(defaultValue bool) bool {
+	if b.MapRoles == nil {
+		return defaultValue
+	}
+
+	return *b.MapRoles
+}
+
+// PolicyWith returns the policy of the Binding merged with the specified global one.
+func (b *Binding) PolicyWith(global *orgs.Policy) *orgs.Policy {
+	return global.Merge(&orgs.Policy{Deny: b.Deny, Protect: b.Protect})
+}
@@ -0,0 +1,187 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/manifest"
+	"github.com/falcosecurity/peribolos-syncer/internal/owners"
+)
+
+var _ = Describe("Loading a manifest", func() {
+	var (
+		err     error
+		content string
+		m       *manifest.Manifest
+	)
+
+	JustBeforeEach(func() {
+		path := filepath.Join(GinkgoT().TempDir(), "manifest.yaml")
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+
+		m, err = manifest.Load(path)
+	})
+
+	Context("the manifest is valid", func() {
+		BeforeEach(func() {
+			content = `
+bindings:
+- team: app-maintainers
+  source:
+    repository: app
+    git_ref: main
+    path: pkg
+  roles: approvers
+  map_roles: true
+- team: libs-reviewers
+  source:
+    repository: libs
+  roles: reviewers
+`
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should default the name", func() {
+			Expect(m.Name).To(Equal("manifest"))
+		})
+
+		It("should load the bindings", func() {
+			Expect(m.Bindings).To(HaveLen(2))
+		})
+
+		It("should build the owners loading options", func() {
+			Expect(m.Bindings[0].OwnersLoadingOptions()).To(Equal(&owners.OwnersLoadingOptions{
+				RepositoryName: "app",
+				GitRef:         "main",
+				ConfigPath:     "pkg",
+				ApproversOnly:  true,
+			}))
+			Expect(m.Bindings[1].OwnersLoadingOptions()).To(Equal(&owners.OwnersLoadingOptions{
+				RepositoryName: "libs",
+				GitRef:         owners.DefaultGitRef,
+				ReviewersOnly:  true,
+			}))
+		})
+
+		It("should fall back to the default role mapping", func() {
+			Expect(m.Bindings[0].MapRolesOr(false)).To(BeTrue())
+			Expect(m.Bindings[1].MapRolesOr(false)).To(BeFalse())
+		})
+	})
+
+	Context("a team is bound twice", func() {
+		BeforeEach(func() {
+			content = `
+bindings:
+- team: app-maintainers
+  source:
+    repository: app
+- team: app-maintainers
+  source:
+    repository: libs
+`
+		})
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("the roles filter is unknown", func() {
+		BeforeEach(func() {
+			content = `
+bindings:
+- team: app-maintainers
+  source:
+    repository: app
+  roles: owners
+`
+		})
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("the source type is unknown", func() {
+		BeforeEach(func() {
+			content = `
+bindings:
+- team: app-maintainers
+  source:
+    type: unknown
+    repository: app
+`
+		})
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("the source is an expression", func() {
+		BeforeEach(func() {
+			content = `
+bindings:
+- team: app-maintainers
+  source:
+    type: expression
+    expression: approvers(app) | approvers(libs) - team(bots)
+`
+		})
+
+		It("should not require a repository", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("the source expression is invalid", func() {
+		BeforeEach(func() {
+			content = `
+bindings:
+- team: app-maintainers
+  source:
+    type: expression
+    expression: approvers(app) |
+`
+		})
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("a field is unknown", func() {
+		BeforeEach(func() {
+			content = `
+bindings:
+- team: app-maintainers
+  repository: app
+`
+		})
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,195 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+const (
+	// FormatTable represents the human readable table output format.
+	FormatTable = "table"
+
+	// FormatJSON represents the JSON output format.
+	FormatJSON = "json"
+
+	// FormatDiff represents the unified diff output format.
+	FormatDiff = "diff"
+
+	diffContextLines = 3
+)
+
+// Formats represents the supported output formats for changes.
+var Formats = []string{FormatTable, FormatJSON, FormatDiff}
+
+// ValidateFormat validates the specified changes output format. It possibly returns an error.
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+
+	//nolint:goerr113
+	return fmt.Errorf("unknown output format %q, must be one of %v", format, Formats)
+}
+
+// ConfigDiff represents the content of a config file before and after the changes.
+type ConfigDiff struct {
+	Path   string
+	Before []byte
+	After  []byte
+}
+
+// PrintChanges writes the Teams changes in the specified format. The diff format writes the unified diff of the
+// config file. It possibly returns an error.
+func PrintChanges(w io.Writer, format string, changes []*orgs.TeamChanges, diff *ConfigDiff) error {
+	switch format {
+	case FormatJSON:
+		return printChangesJSON(w, changes)
+	case FormatDiff:
+		return printConfigDiff(w, diff)
+	default:
+		return printChangesTable(w, changes)
+	}
+}
+
+func printChangesTable(w io.Writer, changes []*orgs.TeamChanges) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "TEAM\tROLE\tCHANGE\tHANDLE")
+
+	for _, c := range changes {
+		if c.Created {
+			fmt.Fprintf(tw, "%s\t-\tcreated\t-\n", c.Team)
+		}
+
+		for _, role := range []struct {
+			name    string
+			changes orgs.Changes
+		}{
+			{"maintainers", c.Maintainers},
+			{"members", c.Members},
+		} {
+			for _, v := range role.changes.Added {
+				fmt.Fprintf(tw, "%s\t%s\t+\t%s\n", c.Team, role.name, v)
+			}
+
+			for _, v := range role.changes.Removed {
+				fmt.Fprintf(tw, "%s\t%s\t-\t%s\n", c.Team, role.name, v)
+			}
+		}
+
+		for _, v := range c.OrgMembers {
+			fmt.Fprintf(tw, "%s\torg members\t+\t%s\n", c.Team, v)
+		}
+
+		for _, v := range c.Denied {
+			fmt.Fprintf(tw, "%s\t-\tdenied\t%s\n", c.Team, v)
+		}
+
+		for _, v := range c.Protected {
+			fmt.Fprintf(tw, "%s\t-\tprotected\t%s\n", c.Team, v)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return errors.Wrap(err, "error writing changes table")
+	}
+
+	return nil
+}
+
+// PrintFiltered prints the handles that the policies filtered out of the Teams changes, if any.
+func PrintFiltered(changes []*orgs.TeamChanges) {
+	for _, c := range changes {
+		if !c.Filtered.Empty() {
+			Print(fmt.Sprintf("Team %s filtered out:\n%s", c.Team, c.FilteredString()))
+		}
+	}
+}
+
+// PrintViolations prints the changes beyond the guardrails, if any.
+func PrintViolations(violations []syncer.Violation) {
+	if len(violations) == 0 {
+		return
+	}
+
+	var b strings.Builder
+
+	b.WriteString("Changes beyond the guardrails:")
+
+	for i := range violations {
+		fmt.Fprintf(&b, "\n- %s", &violations[i])
+	}
+
+	Print(b.String())
+}
+
+func printChangesJSON(w io.Writer, changes []*orgs.TeamChanges) error {
+	b, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "error marshaling changes")
+	}
+
+	if _, err = fmt.Fprintln(w, string(b)); err != nil {
+		return errors.Wrap(err, "error writing changes")
+	}
+
+	return nil
+}
+
+func printConfigDiff(w io.Writer, diff *ConfigDiff) error {
+	if diff == nil {
+		return errors.New("config diff is empty")
+	}
+
+	s, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(diff.Before),
+		B:        splitLines(diff.After),
+		FromFile: "a/" + diff.Path,
+		ToFile:   "b/" + diff.Path,
+		Context:  diffContextLines,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error computing config diff")
+	}
+
+	if _, err = io.WriteString(w, s); err != nil {
+		return errors.Wrap(err, "error writing config diff")
+	}
+
+	return nil
+}
+
+// splitLines splits the content in lines, keeping the line endings and without a trailing empty line.
+func splitLines(b []byte) []string {
+	lines := strings.SplitAfter(string(b), "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
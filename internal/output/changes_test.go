@@ -0,0 +1,112 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/output"
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+var _ = Describe("Printing changes", func() {
+	var (
+		err     error
+		buf     *bytes.Buffer
+		format  string
+		changes []*orgs.TeamChanges
+		diff    *output.ConfigDiff
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		changes = []*orgs.TeamChanges{{
+			Org:     "acme",
+			Team:    "app",
+			Members: orgs.Changes{Added: []string{"charlie"}, Removed: []string{"bob"}},
+		}}
+		diff = &output.ConfigDiff{
+			Path:   "org.yaml",
+			Before: []byte("members:\n- alice\n- bob\n"),
+			After:  []byte("members:\n- alice\n- charlie\n"),
+		}
+	})
+
+	JustBeforeEach(func() {
+		err = output.PrintChanges(buf, format, changes, diff)
+	})
+
+	Context("as a table", func() {
+		BeforeEach(func() {
+			format = output.FormatTable
+		})
+
+		It("should print a row per handle", func() {
+			Expect(err).To(Succeed())
+			Expect(buf.String()).To(Equal(`TEAM  ROLE     CHANGE  HANDLE
+app   members  +       charlie
+app   members  -       bob
+`))
+		})
+	})
+
+	Context("as json", func() {
+		BeforeEach(func() {
+			format = output.FormatJSON
+		})
+
+		It("should print the changes", func() {
+			Expect(err).To(Succeed())
+
+			printed := []*orgs.TeamChanges{}
+			Expect(json.Unmarshal(buf.Bytes(), &printed)).To(Succeed())
+			Expect(printed).To(Equal(changes))
+		})
+	})
+
+	Context("as a unified diff", func() {
+		BeforeEach(func() {
+			format = output.FormatDiff
+		})
+
+		It("should print the config diff", func() {
+			Expect(err).To(Succeed())
+			Expect(buf.String()).To(Equal(`--- a/org.yaml
++++ b/org.yaml
+@@ -1,3 +1,3 @@
+ members:
+ - alice
+-- bob
++- charlie
+`))
+		})
+	})
+})
+
+var _ = Describe("Validating the output format", func() {
+	It("should accept the supported formats", func() {
+		for _, f := range output.Formats {
+			Expect(output.ValidateFormat(f)).To(Succeed())
+		}
+	})
+
+	It("should reject unknown formats", func() {
+		Expect(output.ValidateFormat("yaml")).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,90 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+// FindingsFormats represents the supported output formats for findings.
+var FindingsFormats = []string{FormatTable, FormatJSON}
+
+// ValidateFindingsFormat validates the specified findings output format. It possibly returns an error.
+func ValidateFindingsFormat(format string) error {
+	for _, f := range FindingsFormats {
+		if f == format {
+			return nil
+		}
+	}
+
+	//nolint:goerr113
+	return fmt.Errorf("unknown output format %q, must be one of %v", format, FindingsFormats)
+}
+
+// PrintFindings writes the findings of the validation of a Peribolos config in the specified format.
+// It possibly returns an error.
+func PrintFindings(w io.Writer, format string, findings []orgs.Finding) error {
+	if format == FormatJSON {
+		b, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "error marshaling findings")
+		}
+
+		if _, err = fmt.Fprintln(w, string(b)); err != nil {
+			return errors.Wrap(err, "error writing findings")
+		}
+
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "RULE\tORG\tTEAM\tMESSAGE")
+
+	for _, f := range findings {
+		team := f.Team
+		if team == "" {
+			team = "-"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", f.Rule, f.Org, team, f.Message)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return errors.Wrap(err, "error writing findings table")
+	}
+
+	return nil
+}
+
+// PrintNewFindings prints the findings introduced by the changes to a Peribolos config, if any.
+// It possibly returns an error.
+func PrintNewFindings(findings []orgs.Finding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+
+	Print("Changes making the Peribolos config invalid:")
+
+	return PrintFindings(os.Stdout, FormatTable, findings)
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/output"
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+var _ = Describe("Printing findings", func() {
+	It("should print a row per finding", func() {
+		buf := &bytes.Buffer{}
+
+		Expect(output.PrintFindings(buf, output.FormatTable, []orgs.Finding{
+			{Rule: orgs.RuleAdminAndMember, Org: "acme", Handle: "alice", Message: "alice is both an admin and a member"},
+			{Rule: orgs.RuleDuplicate, Org: "acme", Team: "app", Handle: "bob", Message: "bob is listed more than once in members"},
+		})).To(Succeed())
+		Expect(buf.String()).To(Equal(`RULE              ORG   TEAM  MESSAGE
+admin-and-member  acme  -     alice is both an admin and a member
+duplicate         acme  app   bob is listed more than once in members
+`))
+	})
+
+	It("should reject the diff format", func() {
+		Expect(output.ValidateFindingsFormat(output.FormatDiff)).ToNot(Succeed())
+	})
+})
@@ -15,17 +15,64 @@
 package output
 
 import (
+	"errors"
 	"fmt"
 	"os"
 )
 
+const (
+	// ExitCodeSuccess is the exit code returned on success.
+	ExitCodeSuccess = 0
+
+	// ExitCodeError is the exit code returned on error.
+	ExitCodeError = 1
+
+	// ExitCodeNoChanges is the exit code returned when there is nothing to synchronize.
+	ExitCodeNoChanges = 2
+
+	// ExitCodeDrift is the exit code returned when a check finds that the config is not in sync.
+	ExitCodeDrift = 3
+
+	// ExitCodeInvalid is the exit code returned when a validation finds problems in the config.
+	ExitCodeInvalid = 4
+)
+
+// ExitError represents an outcome that terminates the process with a specific exit code.
+type ExitError struct {
+	// Code represents the process exit code.
+	Code int
+
+	// Message represents the message to print before exiting.
+	Message string
+}
+
+// NewExitError returns a new ExitError with the specified exit code and message.
+func NewExitError(code int, message string) *ExitError {
+	return &ExitError{Code: code, Message: message}
+}
+
+func (e *ExitError) Error() string {
+	return e.Message
+}
+
 func ExitOnErr(err error) {
-	if err != nil {
-		//nolint:forbidigo
-		fmt.Println(err)
+	if err == nil {
+		os.Exit(ExitCodeSuccess)
 	}
 
-	os.Exit(1)
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		if exitErr.Message != "" {
+			Print(exitErr.Message)
+		}
+
+		os.Exit(exitErr.Code)
+	}
+
+	//nolint:forbidigo
+	fmt.Println(err)
+
+	os.Exit(ExitCodeError)
 }
 
 func Print(s string) {
@@ -0,0 +1,257 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owners
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/test-infra/prow/pkg/layeredsets"
+	"k8s.io/test-infra/prow/plugins/ownersconfig"
+	"k8s.io/test-infra/prow/repoowners"
+)
+
+// baseDir represents the root directory of the Owners hierarchy, by repoowners convention.
+const baseDir = ""
+
+// Hierarchy represents an Owners hierarchy, from which people are loaded.
+type Hierarchy interface {
+	// Approvers returns the approvers of the specified path, including the ones of its parent directories.
+	Approvers(path string) layeredsets.String
+
+	// Reviewers returns the reviewers of the specified path, including the ones of its parent directories.
+	Reviewers(path string) layeredsets.String
+
+	// AllApprovers returns the approvers of the whole hierarchy.
+	AllApprovers() sets.String
+
+	// AllReviewers returns the reviewers of the whole hierarchy.
+	AllReviewers() sets.String
+}
+
+// LocalOwners represents an Owners hierarchy loaded from a local checkout of a git repository. It follows the same
+// rules of the Prow repoowners package: aliases are resolved, full configs' filters are applied and parent Owners are
+// not inherited when disabled.
+type LocalOwners struct {
+	aliases repoowners.RepoAliases
+
+	approvers      map[string]map[*regexp.Regexp]sets.String
+	reviewers      map[string]map[*regexp.Regexp]sets.String
+	noParentOwners map[string]bool
+}
+
+// LoadLocalOwners walks the OWNERS files of the specified local directory and returns their hierarchy. The aliases
+// are resolved from the OWNERS_ALIASES file in the directory, if any. It possibly returns an error.
+func LoadLocalOwners(dir string) (*LocalOwners, error) {
+	o, err := newLocalOwners(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		if !info.Mode().IsRegular() || info.Name() != ownersconfig.DefaultOwnersFile {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil {
+			return errors.Wrapf(err, "error getting relative path of %s", path)
+		}
+
+		return o.load(path, canonicalize(rel))
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error walking owners files")
+	}
+
+	return o, nil
+}
+
+// LoadOwnersFile loads only the specified simple OWNERS file, as the root of a hierarchy. The aliases are resolved
+// from the OWNERS_ALIASES file next to it, if any. It possibly returns an error.
+func LoadOwnersFile(path string) (*LocalOwners, error) {
+	o, err := newLocalOwners(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading owners file")
+	}
+
+	simple, err := repoowners.LoadSimpleConfig(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling owners")
+	}
+
+	o.apply(baseDir, nil, &simple.Config)
+
+	return o, nil
+}
+
+// newLocalOwners returns an empty hierarchy, with the aliases of the OWNERS_ALIASES file in the specified directory,
+// if any. It possibly returns an error.
+func newLocalOwners(dir string) (*LocalOwners, error) {
+	o := &LocalOwners{
+		approvers:      map[string]map[*regexp.Regexp]sets.String{},
+		reviewers:      map[string]map[*regexp.Regexp]sets.String{},
+		noParentOwners: map[string]bool{},
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, ownersconfig.DefaultOwnersAliasesFile))
+	switch {
+	case err == nil:
+		if o.aliases, err = repoowners.ParseAliasesConfig(b); err != nil {
+			return nil, errors.Wrap(err, "error parsing owners aliases file")
+		}
+	case !os.IsNotExist(err):
+		return nil, errors.Wrap(err, "error reading owners aliases file")
+	}
+
+	return o, nil
+}
+
+// load loads the OWNERS file at the specified path, applying it to the specified directory of the hierarchy.
+func (o *LocalOwners) load(path, dir string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "error reading owners file %s", path)
+	}
+
+	simple, err := repoowners.LoadSimpleConfig(b)
+	if err == nil && !simple.Empty() {
+		o.apply(dir, nil, &simple.Config)
+		o.noParentOwners[dir] = simple.Options.NoParentOwners
+
+		return nil
+	}
+
+	full, err := repoowners.LoadFullConfig(b)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing owners file %s", path)
+	}
+
+	for pattern, config := range full.Filters {
+		var re *regexp.Regexp
+
+		if pattern != ".*" {
+			if re, err = regexp.Compile(pattern); err != nil {
+				return errors.Wrapf(err, "error compiling filter %q of owners file %s", pattern, path)
+			}
+		}
+
+		config := config
+		o.apply(dir, re, &config)
+	}
+
+	o.noParentOwners[dir] = full.Options.NoParentOwners
+
+	return nil
+}
+
+func (o *LocalOwners) apply(dir string, re *regexp.Regexp, config *repoowners.Config) {
+	if len(config.Approvers) > 0 {
+		if o.approvers[dir] == nil {
+			o.approvers[dir] = map[*regexp.Regexp]sets.String{}
+		}
+
+		o.approvers[dir][re] = o.aliases.ExpandAliases(repoowners.NormLogins(config.Approvers))
+	}
+
+	if len(config.Reviewers) > 0 {
+		if o.reviewers[dir] == nil {
+			o.reviewers[dir] = map[*regexp.Regexp]sets.String{}
+		}
+
+		o.reviewers[dir][re] = o.aliases.ExpandAliases(repoowners.NormLogins(config.Reviewers))
+	}
+}
+
+// Approvers returns the approvers of the specified path, including the ones of its parent directories.
+func (o *LocalOwners) Approvers(path string) layeredsets.String {
+	return o.entriesForPath(path, o.approvers)
+}
+
+// Reviewers returns the reviewers of the specified path, including the ones of its parent directories.
+func (o *LocalOwners) Reviewers(path string) layeredsets.String {
+	return o.entriesForPath(path, o.reviewers)
+}
+
+// AllApprovers returns the approvers of the whole hierarchy.
+func (o *LocalOwners) AllApprovers() sets.String {
+	return all(o.approvers)
+}
+
+// AllReviewers returns the reviewers of the whole hierarchy.
+func (o *LocalOwners) AllReviewers() sets.String {
+	return all(o.reviewers)
+}
+
+// entriesForPath returns the people of the path and of its parent directories, until the root or until a directory
+// that does not inherit parent Owners.
+func (o *LocalOwners) entriesForPath(path string, people map[string]map[*regexp.Regexp]sets.String) layeredsets.String {
+	out := layeredsets.NewString()
+
+	for d, layer := canonicalize(path), 0; ; d, layer = canonicalize(filepath.Dir(d)), layer+1 {
+		relative, err := filepath.Rel(d, path)
+		if err != nil {
+			return out
+		}
+
+		for re, s := range people[d] {
+			if re == nil || re.MatchString(relative) {
+				out.Insert(layer, s.List()...)
+			}
+		}
+
+		if d == baseDir || o.noParentOwners[d] {
+			return out
+		}
+	}
+}
+
+func all(people map[string]map[*regexp.Regexp]sets.String) sets.String {
+	result := sets.NewString()
+
+	for _, byFilter := range people {
+		for _, s := range byFilter {
+			result = result.Union(s)
+		}
+	}
+
+	return result
+}
+
+// canonicalize returns the path with the repoowners convention: without trailing slash and empty for the root.
+func canonicalize(path string) string {
+	if path == "." {
+		return baseDir
+	}
+
+	return strings.TrimSuffix(path, "/")
+}
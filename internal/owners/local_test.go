@@ -0,0 +1,143 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owners_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/falcosecurity/peribolos-syncer/internal/owners"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+var _ = Describe("Loading local owners", func() {
+	var (
+		err       error
+		dir       string
+		hierarchy *LocalOwners
+		options   *OwnersLoadingOptions
+		people    *syncer.People
+	)
+
+	writeFile := func(path, content string) {
+		path = filepath.Join(dir, path)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		options = &OwnersLoadingOptions{}
+
+		writeFile("OWNERS_ALIASES", "aliases:\n  core:\n  - alice\n  - bob\n")
+		writeFile("OWNERS", "approvers:\n- core\nreviewers:\n- charlie\n")
+		writeFile("pkg/OWNERS", "approvers:\n- dave\nreviewers:\n- Erin\n")
+		writeFile("pkg/api/OWNERS", "options:\n  no_parent_owners: true\napprovers:\n- frank\n")
+		writeFile("docs/OWNERS", "filters:\n  \".*\":\n    approvers:\n    - grace\n")
+	})
+
+	JustBeforeEach(func() {
+		hierarchy, err = LoadLocalOwners(dir)
+		if err == nil {
+			people = options.LoadPeople(hierarchy)
+		}
+	})
+
+	Context("without path scope", func() {
+		It("should load the people of the whole hierarchy", func() {
+			Expect(err).To(Succeed())
+			Expect(people.Approvers).To(Equal([]string{"alice", "bob", "dave", "frank", "grace"}))
+			Expect(people.Reviewers).To(Equal([]string{"charlie", "erin"}))
+		})
+	})
+
+	Context("with path scope", func() {
+		BeforeEach(func() {
+			options.ConfigPath = "pkg"
+		})
+
+		It("should load the people from the root until the path", func() {
+			Expect(err).To(Succeed())
+			Expect(people.Approvers).To(Equal([]string{"alice", "bob", "dave"}))
+			Expect(people.Reviewers).To(Equal([]string{"charlie", "erin"}))
+		})
+	})
+
+	Context("with path scope not inheriting parent owners", func() {
+		BeforeEach(func() {
+			options.ConfigPath = "pkg/api"
+		})
+
+		It("should stop at the directory", func() {
+			Expect(err).To(Succeed())
+			Expect(people.Approvers).To(Equal([]string{"frank"}))
+			Expect(people.Reviewers).To(BeEmpty())
+		})
+	})
+
+	Context("with approvers only filter", func() {
+		BeforeEach(func() {
+			options.ApproversOnly = true
+		})
+
+		It("should not load the reviewers", func() {
+			Expect(err).To(Succeed())
+			Expect(people.Approvers).ToNot(BeEmpty())
+			Expect(people.Reviewers).To(BeEmpty())
+		})
+	})
+
+	Context("with a malformed OWNERS file", func() {
+		BeforeEach(func() {
+			writeFile("pkg/OWNERS", "approvers: [")
+		})
+
+		It("should fail", func() {
+			Expect(err).ToNot(Succeed())
+		})
+	})
+})
+
+var _ = Describe("Loading a local owners file", func() {
+	var (
+		err       error
+		dir       string
+		hierarchy *LocalOwners
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+
+		Expect(os.MkdirAll(filepath.Join(dir, "sub"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "OWNERS_ALIASES"),
+			[]byte("aliases:\n  core:\n  - alice\n  - bob\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "OWNERS"),
+			[]byte("approvers:\n- core\nreviewers:\n- charlie\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "sub", "OWNERS"), []byte("approvers: ["), 0o600)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		hierarchy, err = LoadOwnersFile(filepath.Join(dir, "OWNERS"))
+	})
+
+	It("should load only the file, with its aliases resolved", func() {
+		Expect(err).To(Succeed())
+		Expect(hierarchy.AllApprovers().List()).To(Equal([]string{"alice", "bob"}))
+		Expect(hierarchy.AllReviewers().List()).To(Equal([]string{"charlie"}))
+	})
+})
@@ -26,7 +26,8 @@ import (
 )
 
 const (
-	baseRef = "master"
+	// DefaultGitRef represents the default git reference at which load the Owners config.
+	DefaultGitRef = "master"
 )
 
 // Handle represents a GitHub user handle.
@@ -58,12 +59,28 @@ func (o *OwnersLoadingOptions) Validate() error {
 		return fmt.Errorf("owners file's github repository is empty")
 	}
 
+	return o.ValidateFilters()
+}
+
+// ValidateFilters validates the role filters. It possibly returns an error.
+func (o *OwnersLoadingOptions) ValidateFilters() error {
+	if o.ApproversOnly && o.ReviewersOnly {
+		//nolint:goerr113
+		return fmt.Errorf("approvers only and reviewers only filters cannot be specified together")
+	}
+
 	return nil
 }
 
 func (o *OwnersLoadingOptions) AddPFlags(pfs *pflag.FlagSet) {
 	pfs.StringVar(&o.RepositoryName, "owners-repository", "", "The name of the github repository from which parse OWNERS file")
-	pfs.StringVarP(&o.GitRef, "owners-git-ref", "r", baseRef, "The base Git reference at which parse the OWNERS hierarchy")
+	pfs.StringVarP(&o.GitRef, "owners-git-ref", "r", DefaultGitRef, "The base Git reference at which parse the OWNERS hierarchy")
+
+	o.AddFilterPFlags(pfs)
+}
+
+// AddFilterPFlags adds the flags of the path scope and role filters to a flag set.
+func (o *OwnersLoadingOptions) AddFilterPFlags(pfs *pflag.FlagSet) {
 	pfs.StringVar(&o.ConfigPath, "owners-config-path", "", "The path to the Owners config file from the root of the Git repository. When specified, they are considered people for which the roles are applied from the root until the specified path.")
 	pfs.BoolVar(&o.ApproversOnly, "approvers-only", false, "Whether to load only the approvers from the Owners config")
 	pfs.BoolVar(&o.ReviewersOnly, "reviewers-only", false, "Whether to load only the reviewers from the Owners config")
@@ -0,0 +1,50 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owners
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// LoadPeople returns the people of the Owners hierarchy, according to the path scope and the role filters of the
+// Owners loading options.
+func (o *OwnersLoadingOptions) LoadPeople(repoOwners Hierarchy) *syncer.People {
+	var approvers, reviewers sets.String
+
+	// Limiting the scope of the roles.
+	if o.ConfigPath != "" {
+		// Approvers and reviewers of the subpart of the repository.
+		approvers = repoOwners.Approvers(o.ConfigPath).Set()
+		reviewers = repoOwners.Reviewers(o.ConfigPath).Set()
+	} else {
+		// Approvers and reviewers of the whole repository.
+		approvers = repoOwners.AllApprovers()
+		reviewers = repoOwners.AllReviewers()
+	}
+
+	people := &syncer.People{}
+
+	if !o.ReviewersOnly {
+		people.Approvers = approvers.List()
+	}
+
+	if !o.ApproversOnly {
+		people.Reviewers = reviewers.List()
+	}
+
+	return people
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owners
+
+import (
+	"github.com/pkg/errors"
+	peribolos "k8s.io/test-infra/prow/config/org"
+	"k8s.io/test-infra/prow/repoowners"
+
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// RemoteSource represents the Owners hierarchy of a remote GitHub repository, as a people source of truth.
+type RemoteSource struct {
+	// Client represents the client loading the Owners hierarchy.
+	Client repoowners.Interface
+
+	// Org represents the name of the GitHub organization of the repository.
+	Org string
+
+	// Options represents the repository, git reference, path scope and role filters of the Owners hierarchy.
+	Options *OwnersLoadingOptions
+}
+
+// LoadPeople returns the people of the remote Owners hierarchy. It possibly returns an error.
+func (s *RemoteSource) LoadPeople(_ *peribolos.FullConfig) (*syncer.People, error) {
+	repoOwners, err := s.Client.LoadRepoOwners(s.Org, s.Options.RepositoryName, s.Options.GitRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error loading owners from repository %s", s.Options.RepositoryName)
+	}
+
+	return s.Options.LoadPeople(repoOwners), nil
+}
+
+// LocalSource represents the Owners hierarchy of a local checkout of a git repository, as a people source of truth.
+type LocalSource struct {
+	// Dir represents the path to the local checkout.
+	Dir string
+
+	// Options represents the path scope and role filters of the Owners hierarchy.
+	Options *OwnersLoadingOptions
+}
+
+// LoadPeople returns the people of the local Owners hierarchy. It possibly returns an error.
+func (s *LocalSource) LoadPeople(_ *peribolos.FullConfig) (*syncer.People, error) {
+	hierarchy, err := LoadLocalOwners(s.Dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading owners")
+	}
+
+	return s.Options.LoadPeople(hierarchy), nil
+}
+
+// FileSource represents a single local OWNERS file, as a people source of truth.
+type FileSource struct {
+	// Path represents the path to the OWNERS file.
+	Path string
+
+	// Options represents the path scope and role filters of the Owners file.
+	Options *OwnersLoadingOptions
+}
+
+// LoadPeople returns the people of the local Owners file. It possibly returns an error.
+func (s *FileSource) LoadPeople(_ *peribolos.FullConfig) (*syncer.People, error) {
+	hierarchy, err := LoadOwnersFile(s.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading owners")
+	}
+
+	return s.Options.LoadPeople(hierarchy), nil
+}
@@ -0,0 +1,285 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roster
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	prowgithub "k8s.io/test-infra/prow/github"
+
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+const (
+	// RoleApprover represents the role of the people that are approvers, that is team maintainers.
+	RoleApprover = "approver"
+
+	// RoleReviewer represents the role of the people that are reviewers, that is team members.
+	RoleReviewer = "reviewer"
+
+	defaultFile = "roster.csv"
+
+	columnHandle  = "handle"
+	columnRole    = "role"
+	columnExpires = "expires"
+
+	dateLayout = "2006-01-02"
+)
+
+// Roles represents the supported roles.
+var Roles = []string{RoleApprover, RoleReviewer}
+
+// Options represents the options to load the people from a roster file.
+type Options struct {
+	// File represents the path to the roster file in the repository. Files with .json extension are parsed as JSON,
+	// the others as CSV.
+	File string
+}
+
+// AddPFlags adds the roster file options' flags to a flag set.
+func (o *Options) AddPFlags(pfs *pflag.FlagSet) {
+	pfs.StringVar(&o.File, "roster-file", defaultFile, "The path to the roster file from the directory of the Peribolos config, or from the root of the repository when specified, either a CSV file or a JSON list (.json) of handles, roles and optional expiry dates")
+}
+
+// Validate validates the roster file options. It possibly returns an error.
+func (o *Options) Validate() error {
+	if o.File == "" {
+		return errors.New("roster file path is empty")
+	}
+
+	return nil
+}
+
+// Entry represents a person of the roster.
+type Entry struct {
+	Handle string `json:"handle"`
+
+	// Role represents either approver or reviewer. When empty, the person is an approver.
+	Role string `json:"role,omitempty"`
+
+	// Expires represents the date from which the person is not part of the team anymore, as YYYY-MM-DD or RFC 3339.
+	Expires string `json:"expires,omitempty"`
+
+	// line represents the line of the entry in the roster file.
+	line int
+
+	// expiry represents the parsed Expires date, if any.
+	expiry time.Time
+}
+
+// Parse returns the people of the content of the roster file who have not expired at the specified time.
+// It possibly returns an error with the line of the invalid entry.
+func (o *Options) Parse(b []byte, now time.Time) (*syncer.People, error) {
+	var (
+		entries []Entry
+		err     error
+	)
+
+	if strings.EqualFold(filepath.Ext(o.File), ".json") {
+		entries, err = parseJSON(b)
+	} else {
+		entries, err = parseCSV(b)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validate(entries); err != nil {
+		return nil, err
+	}
+
+	approvers, reviewers := sets.NewString(), sets.NewString()
+
+	for _, e := range entries {
+		if !e.expiry.IsZero() && !now.Before(e.expiry) {
+			continue
+		}
+
+		if e.Role == RoleReviewer {
+			reviewers.Insert(prowgithub.NormLogin(e.Handle))
+		} else {
+			approvers.Insert(prowgithub.NormLogin(e.Handle))
+		}
+	}
+
+	return &syncer.People{Approvers: approvers.List(), Reviewers: reviewers.List()}, nil
+}
+
+// validate validates the roster entries and parses their expiry dates. It possibly returns an error with the line
+// of the first invalid entry.
+func validate(entries []Entry) error {
+	lines := map[string]int{}
+
+	for i := range entries {
+		e := &entries[i]
+
+		if !syncer.IsValidLogin(e.Handle) {
+			//nolint:goerr113
+			return fmt.Errorf("line %d: malformed login %q", e.line, e.Handle)
+		}
+
+		handle := prowgithub.NormLogin(e.Handle)
+		if line, ok := lines[handle]; ok {
+			//nolint:goerr113
+			return fmt.Errorf("line %d: duplicate handle %q, already at line %d", e.line, e.Handle, line)
+		}
+
+		lines[handle] = e.line
+
+		e.Role = strings.ToLower(strings.TrimSpace(e.Role))
+		if e.Role != "" && e.Role != RoleApprover && e.Role != RoleReviewer {
+			//nolint:goerr113
+			return fmt.Errorf("line %d: unknown role %q, must be one of %v", e.line, e.Role, Roles)
+		}
+
+		expiry, err := parseExpiry(e.Expires)
+		if err != nil {
+			return errors.Wrapf(err, "line %d: malformed expiry date %q", e.line, e.Expires)
+		}
+
+		e.expiry = expiry
+	}
+
+	return nil
+}
+
+func parseExpiry(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(dateLayout, s); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "expected YYYY-MM-DD or RFC 3339")
+	}
+
+	return t, nil
+}
+
+// parseCSV returns the entries of a CSV roster, whose header names the handle, role and expires columns.
+func parseCSV(b []byte) ([]Entry, error) {
+	reader := csv.NewReader(bytes.NewReader(b))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading roster header")
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns[columnHandle]; !ok {
+		//nolint:goerr113
+		return nil, fmt.Errorf("roster has no %s column", columnHandle)
+	}
+
+	field := func(record []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+
+		return ""
+	}
+
+	entries := []Entry{}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading roster")
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		entries = append(entries, Entry{
+			Handle:  field(record, columnHandle),
+			Role:    field(record, columnRole),
+			Expires: field(record, columnExpires),
+			line:    line,
+		})
+	}
+
+	return entries, nil
+}
+
+// parseJSON returns the entries of a JSON roster list, with their lines.
+func parseJSON(b []byte) ([]Entry, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+
+	if t, err := decoder.Token(); err != nil || t != json.Delim('[') {
+		return nil, errors.New("malformed roster, expected a JSON list")
+	}
+
+	entries := []Entry{}
+
+	for decoder.More() {
+		line := lineAt(b, decoder.InputOffset())
+
+		e := Entry{}
+		if err := decoder.Decode(&e); err != nil {
+			return nil, errors.Wrapf(err, "line %d: malformed roster entry", line)
+		}
+
+		e.line = line
+		entries = append(entries, e)
+	}
+
+	if t, err := decoder.Token(); err != nil || t != json.Delim(']') {
+		return nil, errors.New("malformed roster, unterminated JSON list")
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		//nolint:goerr113
+		return nil, fmt.Errorf("line %d: malformed roster, unexpected content after the JSON list",
+			lineAt(b, decoder.InputOffset()))
+	}
+
+	return entries, nil
+}
+
+// lineAt returns the line of the first JSON value starting from the specified offset.
+func lineAt(b []byte, offset int64) int {
+	for int(offset) < len(b) && strings.ContainsRune(" \t\r\n,", rune(b[offset])) {
+		offset++
+	}
+
+	return bytes.Count(b[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,165 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roster_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/roster"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+var _ = Describe("Parsing a roster", func() {
+	var (
+		err     error
+		content string
+		options *roster.Options
+		people  *syncer.People
+		now     = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	JustBeforeEach(func() {
+		people, err = options.Parse([]byte(content), now)
+	})
+
+	Context("with a CSV file", func() {
+		BeforeEach(func() {
+			options = &roster.Options{File: "roster.csv"}
+			content = `# The app team.
+Handle,Role,Expires
+Alice,approver,
+bob,reviewer,2025-01-01
+charlie,,
+dave,reviewer,2024-05-31
+`
+		})
+
+		It("should load the people who have not expired", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(people.Approvers).To(Equal([]string{"alice", "charlie"}))
+			Expect(people.Reviewers).To(Equal([]string{"bob"}))
+		})
+
+		Context("with duplicate handles", func() {
+			BeforeEach(func() {
+				content = "handle,role\nalice,approver\nbob,reviewer\nALICE,reviewer\n"
+			})
+
+			It("should error with the line numbers", func() {
+				Expect(err).To(MatchError(`line 4: duplicate handle "ALICE", already at line 2`))
+			})
+		})
+
+		Context("with unknown roles", func() {
+			BeforeEach(func() {
+				content = "handle,role\nalice,owner\n"
+			})
+
+			It("should error with the line number", func() {
+				Expect(err).To(MatchError(ContainSubstring(`line 2: unknown role "owner"`)))
+			})
+		})
+
+		Context("with malformed logins", func() {
+			BeforeEach(func() {
+				content = "handle\nalice\nbob smith\n"
+			})
+
+			It("should error with the line number", func() {
+				Expect(err).To(MatchError(`line 3: malformed login "bob smith"`))
+			})
+		})
+
+		Context("with malformed expiry dates", func() {
+			BeforeEach(func() {
+				content = "handle,expires\nalice,next year\n"
+			})
+
+			It("should error with the line number", func() {
+				Expect(err).To(MatchError(ContainSubstring(`line 2: malformed expiry date "next year"`)))
+			})
+		})
+
+		Context("without the handle column", func() {
+			BeforeEach(func() {
+				content = "login,role\nalice,approver\n"
+			})
+
+			It("should error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("with a JSON file", func() {
+		BeforeEach(func() {
+			options = &roster.Options{File: "roster.json"}
+			content = `[
+  {"handle": "alice", "role": "approver"},
+  {"handle": "bob", "role": "reviewer", "expires": "2024-06-01T00:00:00Z"},
+  {"handle": "charlie", "role": "reviewer"}
+]
+`
+		})
+
+		It("should load the people who have not expired", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(people.Approvers).To(Equal([]string{"alice"}))
+			Expect(people.Reviewers).To(Equal([]string{"charlie"}))
+		})
+
+		Context("with duplicate handles", func() {
+			BeforeEach(func() {
+				content = `[
+  {"handle": "alice"},
+  {"handle": "alice", "role": "reviewer"}
+]`
+			})
+
+			It("should error with the line numbers", func() {
+				Expect(err).To(MatchError(`line 3: duplicate handle "alice", already at line 2`))
+			})
+		})
+
+		Context("with unknown fields", func() {
+			BeforeEach(func() {
+				content = `[
+  {"handle": "alice"},
+  {"login": "bob"}
+]`
+			})
+
+			It("should error with the line number", func() {
+				Expect(err).To(MatchError(ContainSubstring("line 3: malformed roster entry")))
+			})
+		})
+
+		Context("with content after the list", func() {
+			BeforeEach(func() {
+				content = `[
+  {"handle": "alice"}
+]
+garbage`
+			})
+
+			It("should error with the line number", func() {
+				Expect(err).To(MatchError("line 4: malformed roster, unexpected content after the JSON list"))
+			})
+		})
+	})
+})
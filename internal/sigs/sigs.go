@@ -0,0 +1,233 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/owners"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+const (
+	// RoleChairs represents the chairs of a group.
+	RoleChairs = "chairs"
+
+	// RoleTechLeads represents the technical leads of a group.
+	RoleTechLeads = "tech_leads"
+
+	// RoleSubprojectOwners represents the approvers of the OWNERS files of a group's subprojects.
+	RoleSubprojectOwners = "subproject_owners"
+
+	// GroupSigs represents the special interest groups.
+	GroupSigs = "sigs"
+
+	// GroupWorkingGroups represents the working groups.
+	GroupWorkingGroups = "workinggroups"
+
+	// GroupCommittees represents the committees.
+	GroupCommittees = "committees"
+
+	// GroupUserGroups represents the user groups.
+	GroupUserGroups = "usergroups"
+
+	defaultPath       = "sigs.yaml"
+	defaultTeamFormat = "{dir}"
+)
+
+var (
+	// Roles represents the supported roles.
+	Roles = []string{RoleChairs, RoleTechLeads, RoleSubprojectOwners}
+
+	// Groups represents the supported kinds of groups.
+	Groups = []string{GroupSigs, GroupWorkingGroups, GroupCommittees, GroupUserGroups}
+)
+
+// File represents a Kubernetes community sigs.yaml file.
+type File struct {
+	Sigs          []Group `json:"sigs,omitempty"`
+	WorkingGroups []Group `json:"workinggroups,omitempty"`
+	Committees    []Group `json:"committees,omitempty"`
+	UserGroups    []Group `json:"usergroups,omitempty"`
+}
+
+// Group represents a community group: a SIG, a working group, a committee or a user group.
+type Group struct {
+	Dir         string       `json:"dir"`
+	Name        string       `json:"name"`
+	Leadership  Leadership   `json:"leadership,omitempty"`
+	Subprojects []Subproject `json:"subprojects,omitempty"`
+}
+
+// Leadership represents the leaders of a group.
+type Leadership struct {
+	Chairs    []Lead `json:"chairs,omitempty"`
+	TechLeads []Lead `json:"tech_leads,omitempty"`
+}
+
+// Lead represents a leader of a group.
+type Lead struct {
+	GitHub string `json:"github"`
+	Name   string `json:"name,omitempty"`
+}
+
+// Subproject represents a subproject of a group, owned by the people of its OWNERS files.
+type Subproject struct {
+	Name string `json:"name"`
+
+	// Owners represents the URLs of the OWNERS files of the subproject.
+	Owners []string `json:"owners,omitempty"`
+}
+
+// Options represents the options to synchronize GitHub Teams with the groups of a sigs.yaml file.
+type Options struct {
+	// Repository represents the name of the GitHub repository containing the sigs.yaml file.
+	Repository string
+
+	// GitRef represents the git reference at which read the sigs.yaml file.
+	GitRef string
+
+	// Path represents the path to the sigs.yaml file in the repository.
+	Path string
+
+	// TeamFormat represents the format of the GitHub Team names, where {dir} and {name} are replaced with the ones of
+	// the group.
+	TeamFormat string
+
+	// Groups represents the kinds of groups to synchronize.
+	Groups []string
+
+	// Roles represents the roles of the groups to synchronize.
+	Roles []string
+}
+
+// AddPFlags adds the sigs.yaml options' flags to a flag set.
+func (o *Options) AddPFlags(pfs *pflag.FlagSet) {
+	pfs.StringVar(&o.Repository, "sigs-repository", "", "The name of the github repository containing the sigs.yaml file. It replaces the team and the Owners options, synchronizing a team per group")
+	pfs.StringVar(&o.GitRef, "sigs-git-ref", owners.DefaultGitRef, "The git reference at which read the sigs.yaml file")
+	pfs.StringVar(&o.Path, "sigs-path", defaultPath, "The path to the sigs.yaml file from the root of the repository")
+	pfs.StringVar(&o.TeamFormat, "sigs-team-format", defaultTeamFormat, "The format of the GitHub team names, where {dir} and {name} are replaced with the ones of the group")
+	pfs.StringSliceVar(&o.Groups, "sigs-groups", []string{GroupSigs}, fmt.Sprintf("The kinds of groups to synchronize, any of %v", Groups))
+	pfs.StringSliceVar(&o.Roles, "sigs-roles", Roles, fmt.Sprintf("The roles of the groups to synchronize, any of %v. Chairs and tech leads are approvers, subproject owners are reviewers", Roles))
+}
+
+// Enabled returns whether the teams are synchronized with a sigs.yaml file.
+func (o *Options) Enabled() bool {
+	return o.Repository != ""
+}
+
+// Validate validates the sigs.yaml options. It possibly returns an error.
+func (o *Options) Validate() error {
+	if o.Path == "" {
+		return errors.New("sigs file path is empty")
+	}
+
+	if !strings.Contains(o.TeamFormat, "{dir}") && !strings.Contains(o.TeamFormat, "{name}") {
+		//nolint:goerr113
+		return fmt.Errorf("sigs team format %q contains neither {dir} nor {name}", o.TeamFormat)
+	}
+
+	if err := validateValues("group", o.Groups, Groups); err != nil {
+		return err
+	}
+
+	return validateValues("role", o.Roles, Roles)
+}
+
+func validateValues(kind string, values, supported []string) error {
+	for _, v := range values {
+		found := false
+
+		for _, s := range supported {
+			found = found || v == s
+		}
+
+		if !found {
+			//nolint:goerr113
+			return fmt.Errorf("unknown sigs %s %q, must be one of %v", kind, v, supported)
+		}
+	}
+
+	return nil
+}
+
+// Parse parses the content of a sigs.yaml file. It possibly returns an error.
+func Parse(b []byte) (*File, error) {
+	f := &File{}
+	if err := yaml.Unmarshal(b, f); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling sigs file")
+	}
+
+	return f, nil
+}
+
+// groups returns the groups of the specified kinds.
+func (f *File) groups(kinds []string) []Group {
+	groups := []Group{}
+
+	for _, k := range kinds {
+		switch k {
+		case GroupSigs:
+			groups = append(groups, f.Sigs...)
+		case GroupWorkingGroups:
+			groups = append(groups, f.WorkingGroups...)
+		case GroupCommittees:
+			groups = append(groups, f.Committees...)
+		case GroupUserGroups:
+			groups = append(groups, f.UserGroups...)
+		}
+	}
+
+	return groups
+}
+
+// teamName returns the name of the GitHub Team of the group.
+func (o *Options) teamName(g *Group) string {
+	return strings.NewReplacer("{dir}", g.Dir, "{name}", g.Name).Replace(o.TeamFormat)
+}
+
+// LoadBindings reads the sigs.yaml file from the repository of the specified organization and returns a binding per
+// group. The groups whose Team is not in the Peribolos config are skipped. It possibly returns an error.
+func (o *Options) LoadBindings(client fileGetter, org string, mapRoles bool) ([]syncer.Binding, error) {
+	b, err := client.GetFile(org, o.Repository, o.Path, o.GitRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading sigs file from repository %s", o.Repository)
+	}
+
+	f, err := Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := f.groups(o.Groups)
+	bindings := make([]syncer.Binding, 0, len(groups))
+
+	for i := range groups {
+		bindings = append(bindings, syncer.Binding{
+			Org:      org,
+			Team:     o.teamName(&groups[i]),
+			Source:   &GroupSource{Client: client, Group: groups[i], Roles: o.Roles},
+			MapRoles: mapRoles,
+			Optional: true,
+		})
+	}
+
+	return bindings, nil
+}
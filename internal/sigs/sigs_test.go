@@ -0,0 +1,184 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigs_test
+
+import (
+	"path"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	prowgithub "k8s.io/test-infra/prow/github"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/sigs"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// fakeFiles serves files by org/repo/ref/path.
+type fakeFiles map[string]string
+
+func (f fakeFiles) GetFile(org, repo, filepath, commit string) ([]byte, error) {
+	content, ok := f[path.Join(org, repo, commit, filepath)]
+	if !ok {
+		return nil, &prowgithub.FileNotFound{}
+	}
+
+	return []byte(content), nil
+}
+
+var _ = Describe("Loading sigs bindings", func() {
+	var (
+		err      error
+		files    fakeFiles
+		options  *sigs.Options
+		bindings []syncer.Binding
+	)
+
+	BeforeEach(func() {
+		files = fakeFiles{
+			"acme/community/main/sigs.yaml": `sigs:
+- dir: sig-apps
+  name: Apps
+  leadership:
+    chairs:
+    - github: Alice
+      name: Alice
+    tech_leads:
+    - github: bob
+  subprojects:
+  - name: app-controller
+    owners:
+    - https://raw.githubusercontent.com/acme/app/main/controller/OWNERS
+  - name: app-docs
+    owners:
+    - https://github.com/acme/app/blob/main/docs/OWNERS
+- dir: sig-docs
+  name: Docs
+  leadership:
+    chairs:
+    - github: dave
+workinggroups:
+- dir: wg-policy
+  name: Policy
+  leadership:
+    chairs:
+    - github: erin
+`,
+			"acme/app/main/controller/OWNERS": `approvers:
+- controller-maintainers
+reviewers:
+- frank
+`,
+			"acme/app/main/docs/OWNERS": `approvers:
+- grace
+`,
+			"acme/app/main/OWNERS_ALIASES": `aliases:
+  controller-maintainers:
+  - charlie
+`,
+		}
+		options = &sigs.Options{
+			Repository: "community",
+			GitRef:     "main",
+			Path:       "sigs.yaml",
+			TeamFormat: "{dir}-leads",
+			Groups:     []string{sigs.GroupSigs},
+			Roles:      sigs.Roles,
+		}
+	})
+
+	JustBeforeEach(func() {
+		bindings, err = options.LoadBindings(files, "acme", true)
+	})
+
+	It("should bind an optional team per group", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bindings).To(HaveLen(2))
+		Expect(bindings[0].Team).To(Equal("sig-apps-leads"))
+		Expect(bindings[1].Team).To(Equal("sig-docs-leads"))
+		Expect(bindings[0].Optional).To(BeTrue())
+		Expect(bindings[0].MapRoles).To(BeTrue())
+	})
+
+	It("should load the leads as approvers and the subproject owners as reviewers", func() {
+		people, err := bindings[0].Source.LoadPeople(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(people.Approvers).To(Equal([]string{"alice", "bob"}))
+		Expect(people.Reviewers).To(Equal([]string{"charlie", "grace"}))
+	})
+
+	Context("with the chairs role only", func() {
+		BeforeEach(func() {
+			options.Roles = []string{sigs.RoleChairs}
+		})
+
+		It("should load the chairs only", func() {
+			people, err := bindings[0].Source.LoadPeople(nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(people.Approvers).To(Equal([]string{"alice"}))
+			Expect(people.Reviewers).To(BeEmpty())
+		})
+	})
+
+	Context("with working groups", func() {
+		BeforeEach(func() {
+			options.Groups = []string{sigs.GroupWorkingGroups}
+		})
+
+		It("should bind the working groups", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bindings).To(HaveLen(1))
+			Expect(bindings[0].Team).To(Equal("wg-policy-leads"))
+		})
+	})
+
+	Context("with an unsupported owners url", func() {
+		BeforeEach(func() {
+			files["acme/community/main/sigs.yaml"] = `sigs:
+- dir: sig-apps
+  subprojects:
+  - name: app
+    owners:
+    - https://example.com/OWNERS
+`
+		})
+
+		It("should error", func() {
+			_, err := bindings[0].Source.LoadPeople(nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("without the sigs file", func() {
+		BeforeEach(func() {
+			options.Path = "missing.yaml"
+		})
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Validating sigs options", func() {
+	It("should error on unknown roles", func() {
+		o := &sigs.Options{Path: "sigs.yaml", TeamFormat: "{dir}", Roles: []string{"emeritus"}}
+		Expect(o.Validate()).To(HaveOccurred())
+	})
+
+	It("should error on team formats without placeholders", func() {
+		o := &sigs.Options{Path: "sigs.yaml", TeamFormat: "team"}
+		Expect(o.Validate()).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,141 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sigs
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	peribolos "k8s.io/test-infra/prow/config/org"
+	prowgithub "k8s.io/test-infra/prow/github"
+	"k8s.io/test-infra/prow/plugins/ownersconfig"
+	"k8s.io/test-infra/prow/repoowners"
+
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+const (
+	rawContentHost = "raw.githubusercontent.com"
+	githubHost     = "github.com"
+)
+
+// fileGetter represents the GitHub client operation needed to read a remote file.
+type fileGetter interface {
+	GetFile(org, repo, filepath, commit string) ([]byte, error)
+}
+
+// GroupSource represents a group of a sigs.yaml file, as a people source of truth. Chairs and tech leads are
+// approvers, the approvers of the subprojects' OWNERS files are reviewers.
+type GroupSource struct {
+	Client fileGetter
+
+	Group Group
+
+	// Roles represents the roles of the group to consider.
+	Roles []string
+}
+
+// LoadPeople returns the people of the group. It possibly returns an error.
+func (s *GroupSource) LoadPeople(_ *peribolos.FullConfig) (*syncer.People, error) {
+	roles := sets.NewString(s.Roles...)
+	approvers, reviewers := sets.NewString(), sets.NewString()
+
+	if roles.Has(RoleChairs) {
+		for _, l := range s.Group.Leadership.Chairs {
+			approvers.Insert(prowgithub.NormLogin(l.GitHub))
+		}
+	}
+
+	if roles.Has(RoleTechLeads) {
+		for _, l := range s.Group.Leadership.TechLeads {
+			approvers.Insert(prowgithub.NormLogin(l.GitHub))
+		}
+	}
+
+	if roles.Has(RoleSubprojectOwners) {
+		for _, sp := range s.Group.Subprojects {
+			for _, u := range sp.Owners {
+				owners, err := s.loadOwners(u)
+				if err != nil {
+					return nil, errors.Wrapf(err, "error loading owners of subproject %s", sp.Name)
+				}
+
+				reviewers.Insert(owners.List()...)
+			}
+		}
+	}
+
+	return &syncer.People{Approvers: approvers.List(), Reviewers: reviewers.List()}, nil
+}
+
+// loadOwners returns the approvers of the OWNERS file at the specified URL, with the aliases of its repository
+// resolved.
+func (s *GroupSource) loadOwners(ownersURL string) (sets.String, error) {
+	org, repo, ref, path, err := parseFileURL(ownersURL)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.Client.GetFile(org, repo, path, ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading owners file %s", ownersURL)
+	}
+
+	config, err := repoowners.LoadSimpleConfig(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing owners file %s", ownersURL)
+	}
+
+	var aliases repoowners.RepoAliases
+
+	b, err = s.Client.GetFile(org, repo, ownersconfig.DefaultOwnersAliasesFile, ref)
+
+	var notFound *prowgithub.FileNotFound
+
+	switch {
+	case err == nil:
+		if aliases, err = repoowners.ParseAliasesConfig(b); err != nil {
+			return nil, errors.Wrapf(err, "error parsing owners aliases of repository %s/%s", org, repo)
+		}
+	case !errors.As(err, &notFound):
+		return nil, errors.Wrapf(err, "error reading owners aliases of repository %s/%s", org, repo)
+	}
+
+	return aliases.ExpandAliases(repoowners.NormLogins(config.Approvers)), nil
+}
+
+// parseFileURL returns the organization, the repository, the git reference and the path of a GitHub file URL, either
+// a raw content URL or a blob URL.
+func parseFileURL(fileURL string) (org, repo, ref, path string, err error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return "", "", "", "", errors.Wrapf(err, "error parsing url %s", fileURL)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch {
+	case u.Host == rawContentHost && len(parts) >= 4:
+		return parts[0], parts[1], parts[2], strings.Join(parts[3:], "/"), nil
+	case u.Host == githubHost && len(parts) >= 5 && parts[2] == "blob":
+		return parts[0], parts[1], parts[3], strings.Join(parts[4:], "/"), nil
+	default:
+		//nolint:goerr113
+		return "", "", "", "", fmt.Errorf("unsupported github file url %s", fileURL)
+	}
+}
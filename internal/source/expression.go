@@ -0,0 +1,102 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"path/filepath"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/expression"
+	"github.com/falcosecurity/peribolos-syncer/internal/owners"
+	"github.com/falcosecurity/peribolos-syncer/internal/roster"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+// newRemoteExpression returns the set expression source of truth, whose repository operands are remote repositories
+// of the specified organization. It possibly returns an error.
+func (o *Options) newRemoteExpression(clients *Clients, org string) (syncer.PeopleSource, error) {
+	node, err := expression.Parse(o.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &expression.Source{
+		Expression: node,
+		Org:        org,
+		NewSource: func(operand *expression.Operand) (syncer.PeopleSource, error) {
+			gitRef := operand.GitRef
+			if gitRef == "" {
+				gitRef = owners.DefaultGitRef
+			}
+
+			if operand.Func == expression.FuncRoster {
+				return &roster.RemoteSource{
+					Client:  clients.Files,
+					Org:     org,
+					Repo:    operand.Repository,
+					GitRef:  gitRef,
+					Options: o.rosterOptions(operand),
+				}, nil
+			}
+
+			return &owners.RemoteSource{
+				Client: clients.Owners,
+				Org:    org,
+				Options: &owners.OwnersLoadingOptions{
+					RepositoryName: operand.Repository,
+					GitRef:         gitRef,
+					ConfigPath:     operand.Path,
+				},
+			}, nil
+		},
+	}, nil
+}
+
+// newLocalExpression returns the set expression source of truth, whose repository operands are local checkouts,
+// relative to the specified directory. It possibly returns an error.
+func (o *Options) newLocalExpression(dir, org string) (syncer.PeopleSource, error) {
+	node, err := expression.Parse(o.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return &expression.Source{
+		Expression: node,
+		Org:        org,
+		NewSource: func(operand *expression.Operand) (syncer.PeopleSource, error) {
+			checkout := operand.Repository
+			if !filepath.IsAbs(checkout) {
+				checkout = filepath.Join(dir, checkout)
+			}
+
+			if operand.Func == expression.FuncRoster {
+				return &roster.LocalSource{Dir: checkout, Options: o.rosterOptions(operand)}, nil
+			}
+
+			return &owners.LocalSource{
+				Dir:     checkout,
+				Options: &owners.OwnersLoadingOptions{ConfigPath: operand.Path},
+			}, nil
+		},
+	}, nil
+}
+
+// rosterOptions returns the roster options of a roster operand, whose path overrides the roster file.
+func (o *Options) rosterOptions(operand *expression.Operand) *roster.Options {
+	if operand.Path == "" {
+		return o.Roster
+	}
+
+	return &roster.Options{File: operand.Path}
+}
@@ -0,0 +1,310 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/test-infra/prow/repoowners"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/codeowners"
+	"github.com/falcosecurity/peribolos-syncer/internal/command"
+	"github.com/falcosecurity/peribolos-syncer/internal/derived"
+	"github.com/falcosecurity/peribolos-syncer/internal/expression"
+	"github.com/falcosecurity/peribolos-syncer/internal/maintainers"
+	"github.com/falcosecurity/peribolos-syncer/internal/owners"
+	"github.com/falcosecurity/peribolos-syncer/internal/roster"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+const (
+	// TypeOwners represents the Prow OWNERS hierarchy source of truth.
+	TypeOwners = "owners"
+
+	// TypeCodeOwners represents the GitHub CODEOWNERS file source of truth.
+	TypeCodeOwners = "codeowners"
+
+	// TypeMaintainers represents the maintainers file source of truth, either a Markdown table or a YAML list.
+	TypeMaintainers = "maintainers"
+
+	// TypeCommand represents the external command source of truth, printing the people as JSON.
+	TypeCommand = "command"
+
+	// TypeRoster represents the roster file source of truth, either a CSV file or a JSON list.
+	TypeRoster = "roster"
+
+	// TypeExpression represents the set expression over Owners hierarchies, roster files and Peribolos Teams.
+	TypeExpression = "expression"
+
+	// TypeDerived represents the other Teams of the Peribolos config source of truth.
+	TypeDerived = "derived"
+)
+
+// Types represents the supported source of truth types.
+var Types = []string{TypeOwners, TypeCodeOwners, TypeMaintainers, TypeCommand, TypeRoster, TypeExpression, TypeDerived}
+
+// Options represents the options of the source of truth in a repository.
+type Options struct {
+	// Type represents the type of the source of truth.
+	Type string
+
+	// Maintainers represents the options of the maintainers file source of truth.
+	Maintainers *maintainers.Options
+
+	// Command represents the options of the external command source of truth.
+	Command *command.Options
+
+	// Roster represents the options of the roster file source of truth.
+	Roster *roster.Options
+
+	// Expression represents the set expression of the expression source of truth.
+	Expression string
+
+	// Derived represents the options of the derived Team source of truth.
+	Derived *derived.Options
+}
+
+// NewOptions returns new Options for the default source of truth type.
+func NewOptions() *Options {
+	return &Options{
+		Type:        TypeOwners,
+		Maintainers: &maintainers.Options{},
+		Command:     &command.Options{},
+		Roster:      &roster.Options{},
+		Derived:     &derived.Options{},
+	}
+}
+
+// AddPFlags adds the source of truth options' flags to a flag set.
+func (o *Options) AddPFlags(pfs *pflag.FlagSet) {
+	pfs.StringVar(&o.Type, "source-type", TypeOwners, fmt.Sprintf("The type of the people source of truth in the repository, one of %v", Types))
+
+	o.Maintainers.AddPFlags(pfs)
+	o.Command.AddPFlags(pfs)
+	o.Roster.AddPFlags(pfs)
+	o.Derived.AddPFlags(pfs)
+
+	pfs.StringVar(&o.Expression, "source-expression", "", fmt.Sprintf("The set expression of the expression source of truth, combining %v operands with | (union), & (intersection) and - (difference)", expression.Funcs))
+}
+
+// Validate validates the source of truth options. It possibly returns an error.
+func (o *Options) Validate() error {
+	if err := ValidateType(o.Type); err != nil {
+		return err
+	}
+
+	switch o.Type {
+	case TypeMaintainers:
+		return o.Maintainers.Validate()
+	case TypeCommand:
+		return o.Command.Validate()
+	case TypeRoster:
+		return o.Roster.Validate()
+	case TypeExpression:
+		_, err := expression.Parse(o.Expression)
+
+		return err
+	case TypeDerived:
+		return o.Derived.Validate()
+	default:
+		return nil
+	}
+}
+
+// WithType returns a copy of the options with the specified source of truth type, or the options when empty.
+func (o *Options) WithType(t string) *Options {
+	if t == "" {
+		return o
+	}
+
+	options := *o
+	options.Type = t
+
+	return &options
+}
+
+// WithExpression returns a copy of the options with the specified set expression, or the options when empty.
+func (o *Options) WithExpression(e string) *Options {
+	if e == "" {
+		return o
+	}
+
+	options := *o
+	options.Expression = e
+
+	return &options
+}
+
+// WithDerivedTeams returns a copy of the options with the specified derived Teams, or the options when empty.
+func (o *Options) WithDerivedTeams(teams []string) *Options {
+	if len(teams) == 0 {
+		return o
+	}
+
+	options := *o
+	options.Derived = &derived.Options{Teams: teams}
+
+	return &options
+}
+
+// IsDerived returns whether the source of truth is derived from the other Teams of the Peribolos config, so that its
+// people are loaded after their changes.
+func (o *Options) IsDerived() bool {
+	return o.Type == TypeDerived
+}
+
+// Description returns a description of the source of truth, linking back to it, for the specified location of its
+// repository, like its URL.
+func (o *Options) Description(location string) string {
+	return fmt.Sprintf("Synchronized with %s", o.Reference(location))
+}
+
+// Reference returns a reference to the source of truth, like the OWNERS of its repository, for the specified location
+// of its repository, like its URL or its name.
+func (o *Options) Reference(location string) string {
+	switch o.Type {
+	case TypeExpression:
+		return o.Expression
+	case TypeDerived:
+		return fmt.Sprintf("the teams %s", strings.Join(o.Derived.Teams, ", "))
+	case TypeCommand:
+		return fmt.Sprintf("the output of %s", o.Command.Path)
+	case TypeCodeOwners:
+		return fmt.Sprintf("the CODEOWNERS of %s", location)
+	case TypeMaintainers:
+		return fmt.Sprintf("the maintainers file of %s", location)
+	case TypeRoster:
+		return fmt.Sprintf("the roster file of %s", location)
+	default:
+		return fmt.Sprintf("the OWNERS of %s", location)
+	}
+}
+
+// LinksRepository returns whether the description of the source of truth links to the location of its repository.
+func (o *Options) LinksRepository() bool {
+	switch o.Type {
+	case TypeExpression, TypeDerived, TypeCommand:
+		return false
+	default:
+		return true
+	}
+}
+
+// fileGetter represents the GitHub client operation needed to read a remote file.
+type fileGetter interface {
+	GetFile(org, repo, filepath, commit string) ([]byte, error)
+}
+
+// Clients represents the clients needed by the remote sources of truth.
+type Clients struct {
+	// Owners represents the client loading the remote Owners hierarchies.
+	Owners repoowners.Interface
+
+	// Files represents the client reading the remote files.
+	Files fileGetter
+
+	// Host represents the GitHub host of the remote repositories, like github.com, to link them.
+	Host string
+}
+
+// ValidateType validates the specified source of truth type. It possibly returns an error.
+func ValidateType(t string) error {
+	for _, v := range Types {
+		if v == t {
+			return nil
+		}
+	}
+
+	//nolint:goerr113
+	return fmt.Errorf("unknown source type %q, must be one of %v", t, Types)
+}
+
+// NewRemote returns the source of truth of the specified Team in the remote repository of the Owners loading options.
+// It possibly returns an error.
+func (o *Options) NewRemote(clients *Clients, org, team string, options *owners.OwnersLoadingOptions) (syncer.PeopleSource, error) {
+	switch o.Type {
+	case TypeOwners:
+		return &owners.RemoteSource{Client: clients.Owners, Org: org, Options: options}, nil
+	case TypeCodeOwners:
+		return &codeowners.RemoteSource{
+			Client: clients.Files,
+			Org:    org,
+			Repo:   options.RepositoryName,
+			GitRef: options.GitRef,
+			Path:   options.ConfigPath,
+		}, nil
+	case TypeMaintainers:
+		return &maintainers.RemoteSource{
+			Client:  clients.Files,
+			Org:     org,
+			Repo:    options.RepositoryName,
+			GitRef:  options.GitRef,
+			Options: o.Maintainers,
+		}, nil
+	case TypeCommand:
+		return &command.Source{
+			Options: o.Command,
+			Request: command.Request{
+				Org:        org,
+				Team:       team,
+				Repository: options.RepositoryName,
+				GitRef:     options.GitRef,
+				Path:       options.ConfigPath,
+			},
+		}, nil
+	case TypeRoster:
+		return &roster.RemoteSource{
+			Client:  clients.Files,
+			Org:     org,
+			Repo:    options.RepositoryName,
+			GitRef:  options.GitRef,
+			Options: o.Roster,
+		}, nil
+	case TypeExpression:
+		return o.newRemoteExpression(clients, org)
+	case TypeDerived:
+		return &derived.Source{Org: org, Team: team, Patterns: o.Derived.Teams}, nil
+	default:
+		return nil, ValidateType(o.Type)
+	}
+}
+
+// NewLocal returns the source of truth of the specified Team in the specified local checkout of a repository.
+// It possibly returns an error.
+func (o *Options) NewLocal(dir, org, team string, options *owners.OwnersLoadingOptions) (syncer.PeopleSource, error) {
+	switch o.Type {
+	case TypeOwners:
+		return &owners.LocalSource{Dir: dir, Options: options}, nil
+	case TypeCodeOwners:
+		return &codeowners.LocalSource{Dir: dir, Path: options.ConfigPath}, nil
+	case TypeMaintainers:
+		return &maintainers.LocalSource{Dir: dir, Options: o.Maintainers}, nil
+	case TypeCommand:
+		return &command.Source{
+			Options: o.Command,
+			Request: command.Request{Org: org, Team: team, Path: options.ConfigPath, Dir: dir},
+		}, nil
+	case TypeRoster:
+		return &roster.LocalSource{Dir: dir, Options: o.Roster}, nil
+	case TypeExpression:
+		return o.newLocalExpression(dir, org)
+	case TypeDerived:
+		return &derived.Source{Org: org, Team: team, Patterns: o.Derived.Teams}, nil
+	default:
+		return nil, ValidateType(o.Type)
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	syncergithub "github.com/falcosecurity/peribolos-syncer/internal/github"
+	"github.com/falcosecurity/peribolos-syncer/internal/manifest"
+	"github.com/falcosecurity/peribolos-syncer/internal/owners"
+	"github.com/falcosecurity/peribolos-syncer/internal/sigs"
+	"github.com/falcosecurity/peribolos-syncer/internal/source"
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+const (
+	sigsSyncName = "sigs"
+
+	// syncNameSeparator represents the separator of the parent Teams in the sync name, which is part of a git branch
+	// name: the branch of a child Team cannot be nested in the one of its parent.
+	syncNameSeparator = "--"
+)
+
+// BindingOptions represent the options to bind GitHub Teams to their remote Owners source of truth, either via
+// command flags for a single Team, via a manifest for many Teams or via a sigs.yaml file for a Team per group.
+type BindingOptions struct {
+	*CommonOptions
+
+	// ManifestPath represents the path to the manifest file.
+	ManifestPath string
+
+	// Manifest represents the loaded manifest, if any.
+	Manifest *manifest.Manifest
+
+	// Owners represents the Owners loading options of the single Team binding.
+	Owners *owners.OwnersLoadingOptions
+
+	// Source represents the options of the source of truth in the repositories.
+	Source *source.Options
+
+	// Sigs represents the options of the sigs.yaml file binding a Team per group.
+	Sigs *sigs.Options
+}
+
+// NewBindingOptions returns new BindingOptions.
+func NewBindingOptions() *BindingOptions {
+	return &BindingOptions{
+		CommonOptions: &CommonOptions{},
+		Owners:        &owners.OwnersLoadingOptions{},
+		Source:        source.NewOptions(),
+		Sigs:          &sigs.Options{},
+	}
+}
+
+// AddPFlags adds the binding options' flags to a flag set.
+func (o *BindingOptions) AddPFlags(pfs *pflag.FlagSet) {
+	pfs.StringVar(&o.GitHubOrg, "org", "", "The name of the GitHub organization to update configuration for")
+	pfs.StringVar(&o.GitHubTeam, "team", "", "The name of the GitHub team to update configuration for, or its path (e.g. 'platform/infra') for a child team")
+	pfs.StringVar(&o.ManifestPath, "manifest", "", "The path to a manifest file binding many GitHub teams to their Owners source of truth, to be synchronized in a single Pull Request. It replaces the team and the Owners options")
+
+	// Owners options.
+	o.Owners.AddPFlags(pfs)
+	o.Source.AddPFlags(pfs)
+
+	// Sigs options.
+	o.Sigs.AddPFlags(pfs)
+
+	// Common sync options.
+	o.CommonOptions.AddPFlags(pfs)
+}
+
+// Validate validates the binding options. It possibly returns an error.
+func (o *BindingOptions) Validate() error {
+	if o.GitHubOrg == "" {
+		return errors.New("github organization name is empty")
+	}
+
+	if err := o.CommonOptions.Validate(); err != nil {
+		return err
+	}
+
+	if o.Sigs.Enabled() {
+		if o.GitHubTeam != "" || o.ManifestPath != "" {
+			return errors.New("sigs repository cannot be specified together with github team name or manifest")
+		}
+
+		if o.CreateTeam.Enabled {
+			return errors.New("team creation cannot be specified together with sigs repository")
+		}
+
+		return o.Sigs.Validate()
+	}
+
+	if o.GitHubTeam == "" && o.ManifestPath == "" {
+		return errors.New("github team name is empty")
+	}
+
+	if o.GitHubTeam != "" && o.ManifestPath != "" {
+		return errors.New("github team name and manifest cannot be specified together")
+	}
+
+	if o.ManifestPath != "" && o.CreateTeam.Enabled {
+		return errors.New("team creation cannot be specified together with manifest")
+	}
+
+	// Expressions reference their repositories on their own, derived Teams have none.
+	if o.ManifestPath == "" && o.Source.Type != source.TypeExpression && !o.Source.IsDerived() {
+		if err := o.Owners.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return o.Source.Validate()
+}
+
+// UseConfigRepository makes the roster source of truth of the single Team read its file next to the Peribolos config,
+// in its repository and at its git reference, when the Owners repository is not specified.
+func (o *BindingOptions) UseConfigRepository(orgsOptions *orgs.Options) {
+	if o.Sigs.Enabled() || o.ManifestPath != "" || o.Source.Type != source.TypeRoster || o.Owners.RepositoryName != "" {
+		return
+	}
+
+	o.Owners.RepositoryName = orgsOptions.ConfigRepo
+	o.Owners.GitRef = orgsOptions.ConfigBaseRef
+	o.Source.Roster.File = path.Join(path.Dir(orgsOptions.ConfigPath), o.Source.Roster.File)
+}
+
+// LoadBindings returns the bindings of the sigs.yaml file or of the manifest, or the one of the options when neither
+// is specified, with the sources of truth of the remote repositories loaded by the specified clients.
+// It possibly returns an error.
+func (o *BindingOptions) LoadBindings(clients *source.Clients) ([]syncer.Binding, error) {
+	if o.Sigs.Enabled() {
+		bindings, err := o.Sigs.LoadBindings(clients.Files, o.GitHubOrg, o.MapRoles)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range bindings {
+			bindings[i].Policy = o.Policy()
+		}
+
+		return bindings, nil
+	}
+
+	newBinding := func(team string, sourceOptions *source.Options, options *owners.OwnersLoadingOptions,
+		mapRoles bool, policy *orgs.Policy,
+	) (syncer.Binding, error) {
+		s, err := sourceOptions.NewRemote(clients, o.GitHubOrg, team, options)
+		if err != nil {
+			return syncer.Binding{}, err
+		}
+
+		return syncer.Binding{
+			Org:      o.GitHubOrg,
+			Team:     team,
+			Source:   s,
+			MapRoles: mapRoles,
+			Derived:  sourceOptions.IsDerived(),
+			Policy:   policy,
+		}, nil
+	}
+
+	if o.ManifestPath == "" {
+		b, err := newBinding(o.CreateTeam.Team(o.GitHubTeam), o.Source, o.Owners, o.MapRoles, o.Policy())
+		if err != nil {
+			return nil, err
+		}
+
+		b.Create = o.CreateTeam.Metadata(o.Source.Description(o.RepositoryURL(clients.Host)))
+
+		return []syncer.Binding{b}, nil
+	}
+
+	m, err := manifest.Load(o.ManifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	o.Manifest = m
+
+	bindings := make([]syncer.Binding, 0, len(m.Bindings))
+	for i := range m.Bindings {
+		b, err := newBinding(m.Bindings[i].Team, m.Bindings[i].SourceOptionsOr(o.Source),
+			m.Bindings[i].OwnersLoadingOptions(), m.Bindings[i].MapRolesOr(o.MapRoles), m.Bindings[i].PolicyWith(o.Policy()))
+		if err != nil {
+			return nil, err
+		}
+
+		bindings = append(bindings, b)
+	}
+
+	return bindings, nil
+}
+
+// RepositoryURL returns the URL of the path of the repository specified by the Owners options on the specified GitHub
+// host, at their git reference or at the default branch.
+func (o *BindingOptions) RepositoryURL(host string) string {
+	ref := o.Owners.GitRef
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	return fmt.Sprintf("https://%s/%s", host, path.Join(o.GitHubOrg, o.Owners.RepositoryName, "tree", ref,
+		o.Owners.ConfigPath))
+}
+
+// SourceReference returns a reference to the sources of truth of the bound Teams, linking back to the repository of
+// the single Team on the specified GitHub host.
+func (o *BindingOptions) SourceReference(host string) string {
+	switch {
+	case o.Sigs.Enabled():
+		return fmt.Sprintf("the leads and the subprojects' OWNERS of the groups of %s", o.BindingsName())
+	case o.Manifest != nil:
+		return fmt.Sprintf("the sources of truth of the bindings of the %s", o.BindingsName())
+	default:
+		return o.Source.Reference(o.RepositoryURL(host))
+	}
+}
+
+// SyncName returns the name identifying the changes of the sync: sigs for a sigs.yaml file, the manifest name, or the
+// team reference, with the parent Teams separated by -- like parent--child.
+func (o *BindingOptions) SyncName() string {
+	switch {
+	case o.Sigs.Enabled():
+		return sigsSyncName
+	case o.Manifest != nil:
+		return strings.ReplaceAll(o.Manifest.Name, orgs.TeamSeparator, syncNameSeparator)
+	default:
+		return strings.ReplaceAll(o.CreateTeam.Team(o.GitHubTeam), orgs.TeamSeparator, syncNameSeparator)
+	}
+}
+
+// MultiTeam returns whether many Teams are bound, via a manifest or a sigs.yaml file.
+func (o *BindingOptions) MultiTeam() bool {
+	return o.Manifest != nil || o.Sigs.Enabled()
+}
+
+// BindingsName returns a human readable name of the file binding many Teams, if any.
+func (o *BindingOptions) BindingsName() string {
+	switch {
+	case o.Sigs.Enabled():
+		return fmt.Sprintf("%s %s", o.Sigs.Repository, path.Base(o.Sigs.Path))
+	case o.Manifest != nil:
+		return fmt.Sprintf("%s manifest", o.Manifest.Name)
+	default:
+		return ""
+	}
+}
+
+// NewRemoteSyncer returns a Syncer of the bound Teams, loading the people from their remote Owners hierarchy and
+// the Peribolos config from its remote repository, read-only. It possibly returns an error.
+func (o *BindingOptions) NewRemoteSyncer(githubOptions *syncergithub.GitHubOptions,
+	orgsOptions *orgs.Options,
+) (*syncer.Syncer, error) {
+	githubClient, _, err := githubOptions.NewClientFromTokenFile()
+	if err != nil {
+		return nil, err
+	}
+
+	gitClientFactory, err := githubOptions.GetGitClientFactory()
+	if err != nil {
+		return nil, errors.Wrap(err, "error building git client gitclientfactory")
+	}
+
+	// Load the bindings between the teams and their source of truth.
+	bindings, err := o.LoadBindings(&source.Clients{
+		Owners: owners.NewClient(githubClient, gitClientFactory),
+		Files:  githubClient,
+		Host:   githubOptions.Host,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := o.LoadHandleMapping()
+	if err != nil {
+		return nil, err
+	}
+
+	guardrails, err := o.Guardrails()
+	if err != nil {
+		return nil, err
+	}
+
+	return &syncer.Syncer{
+		Bindings: bindings,
+		Store: &syncergithub.RemoteStore{
+			Client:  githubClient,
+			Org:     o.GitHubOrg,
+			Repo:    orgsOptions.ConfigRepo,
+			Path:    orgsOptions.ConfigPath,
+			BaseRef: orgsOptions.ConfigBaseRef,
+		},
+		Reconcile:     o.Reconcile,
+		Handles:       mapping,
+		Guardrails:    guardrails,
+		OrgMembership: syncer.OrgMembership(o.OrgMembership),
+		Validate:      true,
+	}, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/manifest"
+	"github.com/falcosecurity/peribolos-syncer/internal/source"
+	"github.com/falcosecurity/peribolos-syncer/internal/sync"
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+var _ = Describe("Naming the sync", func() {
+	var o *sync.BindingOptions
+
+	BeforeEach(func() {
+		o = sync.NewBindingOptions()
+		o.GitHubTeam = "app"
+	})
+
+	It("should be named after the team", func() {
+		Expect(o.SyncName()).To(Equal("app"))
+	})
+
+	Context("with a child team", func() {
+		BeforeEach(func() {
+			o.GitHubTeam = "platform/infra"
+		})
+
+		It("should not nest the name in the one of the parent team", func() {
+			Expect(o.SyncName()).To(Equal("platform--infra"))
+		})
+	})
+
+	Context("with a child team to create under its parent", func() {
+		BeforeEach(func() {
+			o.GitHubTeam = "infra"
+			o.CreateTeam.Enabled = true
+			o.CreateTeam.Parent = "platform"
+		})
+
+		It("should be named after the team reference", func() {
+			Expect(o.SyncName()).To(Equal("platform--infra"))
+		})
+	})
+
+	Context("with a manifest", func() {
+		BeforeEach(func() {
+			o.Manifest = &manifest.Manifest{Name: "platform/teams"}
+		})
+
+		It("should be named after the manifest", func() {
+			Expect(o.SyncName()).To(Equal("platform--teams"))
+		})
+	})
+})
+
+var _ = Describe("Referencing the source of truth", func() {
+	var o *sync.BindingOptions
+
+	BeforeEach(func() {
+		o = sync.NewBindingOptions()
+		o.GitHubOrg = "acme"
+		o.GitHubTeam = "app"
+		o.Owners.RepositoryName = "app"
+	})
+
+	It("should link the OWNERS of the repository", func() {
+		Expect(o.SourceReference("github.com")).To(Equal("the OWNERS of https://github.com/acme/app/tree/HEAD"))
+	})
+
+	Context("with a codeowners source", func() {
+		BeforeEach(func() {
+			o.Source.Type = source.TypeCodeOwners
+			o.Owners.GitRef = "main"
+		})
+
+		It("should link the CODEOWNERS of the repository", func() {
+			Expect(o.SourceReference("github.com")).To(Equal("the CODEOWNERS of https://github.com/acme/app/tree/main"))
+		})
+	})
+
+	Context("with an expression source", func() {
+		BeforeEach(func() {
+			o.Source.Type = source.TypeExpression
+			o.Source.Expression = "owners(app) - team(bots)"
+			o.Owners.RepositoryName = ""
+		})
+
+		It("should reference the expression", func() {
+			Expect(o.SourceReference("github.com")).To(Equal("owners(app) - team(bots)"))
+		})
+	})
+
+	Context("with a manifest", func() {
+		BeforeEach(func() {
+			o.Manifest = &manifest.Manifest{Name: "platform"}
+		})
+
+		It("should reference the bindings of the manifest", func() {
+			Expect(o.SourceReference("github.com")).To(Equal("the sources of truth of the bindings of the platform manifest"))
+		})
+	})
+})
+
+var _ = Describe("Reading the roster file next to the Peribolos config", func() {
+	var o *sync.BindingOptions
+
+	BeforeEach(func() {
+		o = sync.NewBindingOptions()
+		o.GitHubTeam = "app"
+		o.Source.Type = source.TypeRoster
+		o.Source.Roster.File = "roster.csv"
+	})
+
+	JustBeforeEach(func() {
+		o.UseConfigRepository(&orgs.Options{ConfigRepo: "community", ConfigPath: "config/org.yaml", ConfigBaseRef: "main"})
+	})
+
+	It("should read it from the Peribolos config repository", func() {
+		Expect(o.Owners.RepositoryName).To(Equal("community"))
+		Expect(o.Owners.GitRef).To(Equal("main"))
+		Expect(o.Source.Roster.File).To(Equal("config/roster.csv"))
+	})
+
+	Context("when the owners repository is specified", func() {
+		BeforeEach(func() {
+			o.Owners.RepositoryName = "app"
+		})
+
+		It("should read it from the root of the owners repository", func() {
+			Expect(o.Owners.RepositoryName).To(Equal("app"))
+			Expect(o.Source.Roster.File).To(Equal("roster.csv"))
+		})
+	})
+})
@@ -0,0 +1,101 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+const flagCreateTeamDescription = "create-team-description"
+
+// CreateTeamOptions represent the options to add the Team to the Peribolos config when it is not there yet.
+type CreateTeamOptions struct {
+	// Enabled represents the option to add the missing Team.
+	Enabled bool
+
+	// Description represents the description of the added Team. It defaults to a link to the source of truth.
+	Description string
+
+	// Privacy represents the privacy of the added Team, either closed or secret.
+	Privacy string
+
+	// Parent represents the reference of the parent of the added Team, if any.
+	Parent string
+}
+
+// AddPFlags adds the create team options' flags to a flag set.
+func (o *CreateTeamOptions) AddPFlags(pfs *pflag.FlagSet) {
+	pfs.BoolVar(&o.Enabled, "create-team", false, "Whether to add the GitHub team to the Peribolos config when it is not there yet, instead of failing")
+	pfs.StringVar(&o.Description, flagCreateTeamDescription, "", "The description of the added GitHub team. It defaults to a link to the source of truth, and is required by sync local unless the source of truth is not in the repository")
+	pfs.StringVar(&o.Privacy, "create-team-privacy", string(peribolos.Closed), fmt.Sprintf("The privacy of the added GitHub team, one of %v", []peribolos.Privacy{peribolos.Closed, peribolos.Secret}))
+	pfs.StringVar(&o.Parent, "create-team-parent", "", "The name of the existing parent of the added GitHub team, if any, like parent or grandparent/parent for nested teams")
+}
+
+// Validate validates the create team options. It possibly returns an error.
+func (o *CreateTeamOptions) Validate() error {
+	if !o.Enabled {
+		return nil
+	}
+
+	if privacy := peribolos.Privacy(o.Privacy); privacy != peribolos.Closed && privacy != peribolos.Secret {
+		//nolint:goerr113
+		return fmt.Errorf("unknown team privacy %q, must be one of %v", o.Privacy,
+			[]peribolos.Privacy{peribolos.Closed, peribolos.Secret})
+	}
+
+	if o.Parent == "" {
+		return nil
+	}
+
+	for _, name := range strings.Split(o.Parent, orgs.TeamSeparator) {
+		if name == "" {
+			//nolint:goerr113
+			return fmt.Errorf("malformed parent team %q", o.Parent)
+		}
+	}
+
+	return nil
+}
+
+// Team returns the reference of the specified Team, nested under the parent Team when the Team is added under it.
+func (o *CreateTeamOptions) Team(team string) string {
+	if !o.Enabled || o.Parent == "" {
+		return team
+	}
+
+	return o.Parent + orgs.TeamSeparator + team
+}
+
+// Metadata returns the metadata of the Team to add, with the specified description unless one is set, or nil when
+// the Team is not to be added.
+func (o *CreateTeamOptions) Metadata(description string) *peribolos.TeamMetadata {
+	if !o.Enabled {
+		return nil
+	}
+
+	if o.Description != "" {
+		description = o.Description
+	}
+
+	privacy := peribolos.Privacy(o.Privacy)
+
+	return &peribolos.TeamMetadata{Description: &description, Privacy: &privacy}
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	syncerowners "github.com/falcosecurity/peribolos-syncer/internal/owners"
+	"github.com/falcosecurity/peribolos-syncer/internal/source"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+const (
+	flagOwnersFilePath             = "owners-file"
+	flagOwnersDir                  = "owners-dir"
+	flagIncludeReviewers           = "include-reviewers"
+	flagPeribolosConfigFilepath    = "orgs-config"
+	defaultOwnersFilepath          = "OWNERS"
+	defaultPeribolosConfigFilepath = "org.yaml"
+)
+
+// LocalOptions represent the options to synchronize a GitHub Team with an Owners hierarchy, both on local filesystem.
+type LocalOptions struct {
+	*CommonOptions
+
+	// OwnersFilepath represents the path to the OWNERS file. When OwnersDir is not specified, the hierarchy is
+	// walked from the file's directory and the roles are scoped to the file.
+	OwnersFilepath string
+
+	// OwnersDir represents the path to the local checkout of the repository containing the source of truth.
+	OwnersDir string
+
+	// Owners represents the path scope and the role filters of the Owners hierarchy.
+	Owners *syncerowners.OwnersLoadingOptions
+
+	// IncludeReviewers represents the option to load the reviewers of the Owners too. By default, only the approvers
+	// are loaded, unless the reviewers only filter is specified.
+	IncludeReviewers bool
+
+	// Source represents the options of the source of truth in the local checkout.
+	Source *source.Options
+
+	// PeribolosConfigFilepath represents the path to the Peribolos config file.
+	PeribolosConfigFilepath string
+}
+
+// NewLocalOptions returns new LocalOptions.
+func NewLocalOptions() *LocalOptions {
+	return &LocalOptions{
+		CommonOptions: &CommonOptions{},
+		Owners:        &syncerowners.OwnersLoadingOptions{},
+		Source:        source.NewOptions(),
+	}
+}
+
+// AddPFlags adds the local options' flags to a flag set.
+func (o *LocalOptions) AddPFlags(pfs *pflag.FlagSet) {
+	pfs.StringVarP(&o.OwnersFilepath, flagOwnersFilePath, "o", defaultOwnersFilepath, "The path to the OWNERS file. Only the people of this file are considered, unless --owners-dir is specified")
+	pfs.StringVar(&o.OwnersDir, flagOwnersDir, "", "The path to the local checkout of the repository whose OWNERS hierarchy is walked, with the aliases of its OWNERS_ALIASES file. It replaces the OWNERS file option")
+	pfs.BoolVar(&o.IncludeReviewers, flagIncludeReviewers, false, "Whether to load the reviewers of the Owners too. By default, only the approvers are loaded, unless --reviewers-only is specified")
+	pfs.StringVarP(&o.PeribolosConfigFilepath, flagPeribolosConfigFilepath, "c", defaultPeribolosConfigFilepath, "The path to the Peribolos org.yaml file")
+	pfs.StringVar(&o.GitHubOrg, "org", "", "The name of the GitHub organization to update")
+	pfs.StringVar(&o.GitHubTeam, "team", "", "The name of the GitHub team to update, or its path (e.g. 'platform/infra') for a child team")
+
+	// Owners path scope and role filters.
+	o.Owners.AddFilterPFlags(pfs)
+	o.Source.AddPFlags(pfs)
+
+	// Common sync options.
+	o.CommonOptions.AddPFlags(pfs)
+}
+
+// Validate validates the local options. It possibly returns an error.
+func (o *LocalOptions) Validate() error {
+	if o.GitHubOrg == "" {
+		return errors.New("org name is empty")
+	}
+
+	if o.GitHubTeam == "" {
+		return errors.New("team name is empty")
+	}
+
+	if err := o.CommonOptions.Validate(); err != nil {
+		return err
+	}
+
+	if err := o.Owners.ValidateFilters(); err != nil {
+		return err
+	}
+
+	if o.IncludeReviewers && o.Owners.ApproversOnly {
+		//nolint:goerr113
+		return fmt.Errorf("--%s cannot be specified with the approvers only filter", flagIncludeReviewers)
+	}
+
+	// The local checkout cannot be linked from the description of the added Team.
+	if o.CreateTeam.Enabled && o.CreateTeam.Description == "" && o.Source.LinksRepository() {
+		//nolint:goerr113
+		return fmt.Errorf("--%s is required to add the team with a %s source", flagCreateTeamDescription, o.Source.Type)
+	}
+
+	return o.Source.Validate()
+}
+
+// NewSyncer returns a Syncer of the Team, loading the people from the source of truth of the local checkout and
+// storing the Peribolos config on the local filesystem. It possibly returns an error.
+func (o *LocalOptions) NewSyncer() (*syncer.Syncer, error) {
+	s, dir, err := o.newSource()
+	if err != nil {
+		return nil, err
+	}
+
+	mapping, err := o.LoadHandleMapping()
+	if err != nil {
+		return nil, err
+	}
+
+	guardrails, err := o.Guardrails()
+	if err != nil {
+		return nil, err
+	}
+
+	return &syncer.Syncer{
+		Bindings: []syncer.Binding{{
+			Org:      o.GitHubOrg,
+			Team:     o.CreateTeam.Team(o.GitHubTeam),
+			Source:   s,
+			MapRoles: o.MapRoles,
+			Derived:  o.Source.IsDerived(),
+			Policy:   o.Policy(),
+			Create:   o.CreateTeam.Metadata(o.Source.Description(dir)),
+		}},
+		Store:         &syncer.FileStore{Path: o.PeribolosConfigFilepath},
+		Reconcile:     o.Reconcile,
+		Handles:       mapping,
+		Guardrails:    guardrails,
+		OrgMembership: syncer.OrgMembership(o.OrgMembership),
+		Validate:      true,
+	}, nil
+}
+
+// newSource returns the source of truth of the Team and the directory of its repository. Without a checkout
+// directory, only the people of the OWNERS file are loaded. It possibly returns an error.
+func (o *LocalOptions) newSource() (syncer.PeopleSource, string, error) {
+	dir, options := o.sourceDir(), *o.Owners
+
+	if !o.IncludeReviewers && !options.ReviewersOnly {
+		options.ApproversOnly = true
+	}
+
+	if o.OwnersDir == "" && o.Source.Type == source.TypeOwners {
+		return &syncerowners.FileSource{Path: o.OwnersFilepath, Options: &options}, dir, nil
+	}
+
+	s, err := o.Source.NewLocal(dir, o.GitHubOrg, o.GitHubTeam, &options)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return s, dir, nil
+}
+
+// sourceDir returns the directory of the repository of the source of truth: the checkout directory, or without it
+// the directory of the Peribolos config for a roster file and the one of the OWNERS file otherwise.
+func (o *LocalOptions) sourceDir() string {
+	switch {
+	case o.OwnersDir != "":
+		return o.OwnersDir
+	case o.Source.Type == source.TypeRoster:
+		return filepath.Dir(o.PeribolosConfigFilepath)
+	default:
+		return filepath.Dir(o.OwnersFilepath)
+	}
+}
+
+// SourceReference returns a reference to the source of truth of the Team, like its OWNERS file.
+func (o *LocalOptions) SourceReference() string {
+	if o.OwnersDir == "" && o.Source.Type == source.TypeOwners {
+		return fmt.Sprintf("the OWNERS file %s", o.OwnersFilepath)
+	}
+
+	return o.Source.Reference(o.sourceDir())
+}
@@ -0,0 +1,326 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sync_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/source"
+	"github.com/falcosecurity/peribolos-syncer/internal/sync"
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+var _ = Describe("Planning a sync on the local filesystem", func() {
+	var (
+		err  error
+		o    *sync.LocalOptions
+		plan *syncer.Plan
+	)
+
+	BeforeEach(func() {
+		dir := GinkgoT().TempDir()
+
+		o = sync.NewLocalOptions()
+		o.GitHubOrg = "acme"
+		o.GitHubTeam = "app"
+		o.OwnersFilepath = filepath.Join(dir, "OWNERS")
+		o.PeribolosConfigFilepath = filepath.Join(dir, "org.yaml")
+
+		Expect(os.WriteFile(o.OwnersFilepath, []byte("approvers:\n- alice\n- bob\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(o.PeribolosConfigFilepath,
+			[]byte("orgs:\n  acme:\n    teams:\n      app:\n        members:\n        - alice\n"), 0o600)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		var s *syncer.Syncer
+
+		if s, err = o.NewSyncer(); err == nil {
+			plan, err = s.Plan()
+		}
+	})
+
+	Context("when the team is missing people", func() {
+		It("should report the drift", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.InSync()).To(BeFalse())
+			Expect(plan.Drift()).To(HaveLen(1))
+			Expect(plan.Drift()[0].Members.Added).To(Equal([]string{"bob"}))
+		})
+
+		It("should not write the config", func() {
+			Expect(err).To(Succeed())
+			Expect(os.ReadFile(o.PeribolosConfigFilepath)).To(Equal(plan.Before))
+			Expect(string(plan.After)).To(ContainSubstring("- bob"))
+		})
+	})
+
+	Context("when the team already matches the OWNERS", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(o.OwnersFilepath, []byte("approvers:\n- alice\n"), 0o600)).To(Succeed())
+		})
+
+		It("should be in sync", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.InSync()).To(BeTrue())
+			Expect(plan.Drift()).To(BeEmpty())
+		})
+	})
+
+	Context("when the OWNERS file has reviewers", func() {
+		BeforeEach(func() {
+			dir := filepath.Dir(o.OwnersFilepath)
+
+			Expect(os.WriteFile(o.OwnersFilepath, []byte("approvers:\n- alice\n- bob\nreviewers:\n- charlie\n"),
+				0o600)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(dir, "sub"), 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "sub", "OWNERS"), []byte("approvers: ["), 0o600)).To(Succeed())
+		})
+
+		It("should load only the approvers of the file by default", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.Drift()).To(HaveLen(1))
+			Expect(plan.Drift()[0].Members.Added).To(Equal([]string{"bob"}))
+		})
+
+		Context("when the reviewers are included", func() {
+			BeforeEach(func() {
+				o.IncludeReviewers = true
+			})
+
+			It("should load the reviewers too", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.Drift()[0].Members.Added).To(Equal([]string{"bob", "charlie"}))
+			})
+		})
+	})
+
+	Context("when walking the local checkout", func() {
+		BeforeEach(func() {
+			dir := filepath.Dir(o.OwnersFilepath)
+			o.OwnersDir = dir
+			o.Owners.ReviewersOnly = true
+
+			Expect(os.MkdirAll(filepath.Join(dir, "pkg"), 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "OWNERS_ALIASES"),
+				[]byte("aliases:\n  pkg-reviewers:\n  - charlie\n  - dave\n"), 0o600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "pkg", "OWNERS"),
+				[]byte("reviewers:\n- pkg-reviewers\n"), 0o600)).To(Succeed())
+		})
+
+		It("should load the people of the whole hierarchy with their aliases resolved", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.Drift()).To(HaveLen(1))
+			Expect(plan.Drift()[0].Members.Added).To(Equal([]string{"charlie", "dave"}))
+		})
+	})
+
+	Context("with a CODEOWNERS source", func() {
+		BeforeEach(func() {
+			dir := filepath.Dir(o.OwnersFilepath)
+			o.Source.Type = source.TypeCodeOwners
+
+			Expect(os.MkdirAll(filepath.Join(dir, ".github"), 0o755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"),
+				[]byte("* @Erin @acme/app\n"), 0o600)).To(Succeed())
+		})
+
+		It("should load the code owners, expanding the teams of the config", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.Drift()).To(HaveLen(1))
+			Expect(plan.Drift()[0].Members.Added).To(Equal([]string{"erin"}))
+		})
+	})
+
+	Context("with a roster source next to the config", func() {
+		BeforeEach(func() {
+			o.Source.Type = source.TypeRoster
+			o.Source.Roster.File = "roster.csv"
+			o.OwnersFilepath = filepath.Join(GinkgoT().TempDir(), "OWNERS")
+
+			Expect(os.WriteFile(filepath.Join(filepath.Dir(o.PeribolosConfigFilepath), "roster.csv"),
+				[]byte("handle,role,expires\nalice,approver,\nfrank,reviewer,\ngrace,reviewer,2000-01-01\n"), 0o600)).To(Succeed())
+		})
+
+		It("should load the people who have not expired", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.Drift()).To(HaveLen(1))
+			Expect(plan.Drift()[0].Members.Added).To(Equal([]string{"frank"}))
+		})
+
+		Context("when walking the local checkout", func() {
+			BeforeEach(func() {
+				o.OwnersDir = GinkgoT().TempDir()
+
+				Expect(os.WriteFile(filepath.Join(o.OwnersDir, "roster.csv"), []byte("handle,role\nheidi,approver\n"),
+					0o600)).To(Succeed())
+			})
+
+			It("should read the roster file of the checkout", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.Drift()[0].Members.Added).To(Equal([]string{"heidi"}))
+			})
+		})
+	})
+
+	Context("with an expression source", func() {
+		BeforeEach(func() {
+			dir := filepath.Dir(o.OwnersFilepath)
+			o.Source.Type = source.TypeExpression
+			o.Source.Expression = "(approvers(.) | roster(.:roster.csv)) - team(app)"
+
+			Expect(os.WriteFile(filepath.Join(dir, "roster.csv"),
+				[]byte("handle,role\nalice,approver\nfrank,reviewer\n"), 0o600)).To(Succeed())
+		})
+
+		It("should load the people of the expression", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.Drift()).To(HaveLen(1))
+			Expect(plan.Drift()[0].Members.Added).To(Equal([]string{"bob", "frank"}))
+		})
+	})
+
+	Context("with a handle mapping file", func() {
+		BeforeEach(func() {
+			dir := filepath.Dir(o.OwnersFilepath)
+			o.HandleMappingFile = filepath.Join(dir, "handles.yaml")
+
+			Expect(os.WriteFile(o.OwnersFilepath, []byte("approvers:\n- Alice\n- bob-old\n"), 0o600)).To(Succeed())
+			Expect(os.WriteFile(o.HandleMappingFile, []byte("bob-old: bob\n"), 0o600)).To(Succeed())
+		})
+
+		It("should map the handles before updating the team", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.Drift()).To(HaveLen(1))
+			Expect(plan.Drift()[0].Members.Added).To(Equal([]string{"bob"}))
+		})
+	})
+
+	Context("with deny and protect patterns", func() {
+		BeforeEach(func() {
+			o.Reconcile = true
+			o.DenyHandles = []string{"*[bot]"}
+			o.ProtectHandles = []string{"alice"}
+
+			Expect(os.WriteFile(o.OwnersFilepath, []byte("approvers:\n- bob\n- renovate[bot]\n"), 0o600)).To(Succeed())
+		})
+
+		It("should filter the handles out and report them", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.Drift()).To(HaveLen(1))
+			Expect(plan.Drift()[0].Members).To(Equal(orgs.Changes{Added: []string{"bob"}}))
+			Expect(plan.Drift()[0].Denied).To(Equal([]string{"renovate[bot]"}))
+			Expect(plan.Drift()[0].Protected).To(Equal([]string{"alice"}))
+		})
+	})
+
+	Context("with guardrails", func() {
+		BeforeEach(func() {
+			o.MaxAdded = "50%"
+		})
+
+		It("should report the changes beyond them", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.Violations).To(Equal([]syncer.Violation{{
+				Team:   "app",
+				Reason: "adds 1 of 1 handles, more than the maximum of 50%",
+			}}))
+		})
+	})
+
+	Context("when the team to create does not exist", func() {
+		BeforeEach(func() {
+			o.GitHubTeam = "ui"
+			o.CreateTeam = sync.CreateTeamOptions{
+				Enabled: true, Description: "The UI team.", Privacy: "secret", Parent: "app",
+			}
+		})
+
+		It("should create it under its parent", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.Drift()).To(HaveLen(1))
+			Expect(plan.Drift()[0].Team).To(Equal("app/ui"))
+			Expect(plan.Drift()[0].Created).To(BeTrue())
+			Expect(plan.Drift()[0].Members.Added).To(Equal([]string{"alice", "bob"}))
+			Expect(string(plan.After)).To(ContainSubstring(`        teams:
+          ui:
+            description: The UI team.
+            members:
+            - alice
+            - bob
+            privacy: secret
+`))
+		})
+
+		Context("without description", func() {
+			BeforeEach(func() {
+				o.CreateTeam.Description = ""
+				o.OrgMembership = string(syncer.OrgMembershipIgnore)
+			})
+
+			It("should not be valid, as the local checkout cannot be linked", func() {
+				Expect(o.Validate()).To(MatchError(ContainSubstring("--create-team-description is required")))
+			})
+		})
+	})
+
+	Context("when the team does not exist", func() {
+		BeforeEach(func() {
+			o.GitHubTeam = "unknown"
+		})
+
+		It("should fail", func() {
+			Expect(err).ToNot(Succeed())
+		})
+	})
+})
+
+var _ = Describe("Referencing the local source of truth", func() {
+	var o *sync.LocalOptions
+
+	BeforeEach(func() {
+		o = sync.NewLocalOptions()
+		o.OwnersFilepath = "app/OWNERS"
+		o.PeribolosConfigFilepath = "config/org.yaml"
+	})
+
+	It("should reference the OWNERS file", func() {
+		Expect(o.SourceReference()).To(Equal("the OWNERS file app/OWNERS"))
+	})
+
+	Context("when walking the local checkout", func() {
+		BeforeEach(func() {
+			o.OwnersDir = "app"
+		})
+
+		It("should reference the OWNERS of the checkout", func() {
+			Expect(o.SourceReference()).To(Equal("the OWNERS of app"))
+		})
+	})
+
+	Context("with a roster source", func() {
+		BeforeEach(func() {
+			o.Source.Type = source.TypeRoster
+		})
+
+		It("should reference the roster file next to the config", func() {
+			Expect(o.SourceReference()).To(Equal("the roster file of config"))
+		})
+	})
+})
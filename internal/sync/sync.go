@@ -14,8 +14,121 @@
 
 package sync
 
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/falcosecurity/peribolos-syncer/internal/handles"
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
 // CommonOptions represent the sync command common options.
 type CommonOptions struct {
 	GitHubTeam string
 	GitHubOrg  string
+
+	// Reconcile represents the option to remove from the Team the people not in the source of truth anymore.
+	Reconcile bool
+
+	// MapRoles represents the option to map approvers to Team maintainers and reviewers to Team members.
+	MapRoles bool
+
+	// HandleMappingFile represents the path to the file mapping the handles of the source of truth to GitHub logins.
+	HandleMappingFile string
+
+	// DenyHandles represents the patterns of the handles never added to any Team.
+	DenyHandles []string
+
+	// ProtectHandles represents the patterns of the handles never removed from any Team.
+	ProtectHandles []string
+
+	// MaxAdded represents the maximum number, or percentage, of handles added to any Team.
+	MaxAdded string
+
+	// MaxRemoved represents the maximum number, or percentage, of handles removed from any Team.
+	MaxRemoved string
+
+	// MinTeamSize represents the size under which the people of any Team are not removed anymore.
+	MinTeamSize int
+
+	// OverrideGuardrails represents the option to apply the changes beyond the guardrails anyway.
+	OverrideGuardrails bool
+
+	// OrgMembership represents how the people added to the Teams that are not members of the Organization are handled.
+	OrgMembership string
+
+	// CreateTeam represents the options to add the Team to the Peribolos config when it is not there yet.
+	CreateTeam CreateTeamOptions
+}
+
+// AddPFlags adds the common sync options' flags to a flag set.
+func (o *CommonOptions) AddPFlags(pfs *pflag.FlagSet) {
+	pfs.BoolVar(&o.Reconcile, "reconcile", false, "Whether to also remove from the GitHub team the people that are not in the source of truth anymore, so that the team exactly matches it")
+	pfs.BoolVar(&o.MapRoles, "map-roles", false, "Whether to map the approvers to GitHub team maintainers and the reviewers that are not approvers to GitHub team members, instead of adding everyone as members")
+	pfs.StringSliceVar(&o.DenyHandles, "deny-handles", nil, "The patterns of the handles never added to the GitHub teams, like '*[bot]', where * matches any sequence of characters")
+	pfs.StringSliceVar(&o.ProtectHandles, "protect-handles", nil, "The patterns of the handles never removed from the GitHub teams, like break-glass admins, where * matches any sequence of characters")
+	pfs.StringVar(&o.HandleMappingFile, "handle-mapping-file", "", "The path to a YAML file mapping the handles of the source of truth, like old logins or aliases, to GitHub logins")
+	pfs.StringVar(&o.MaxAdded, "max-added", "", "The maximum number, like 10, or percentage, like 20%, of handles added to a GitHub team by a sync")
+	pfs.StringVar(&o.MaxRemoved, "max-removed", "", "The maximum number, like 10, or percentage, like 20%, of handles removed from a GitHub team by a sync")
+	pfs.IntVar(&o.MinTeamSize, "min-team-size", 0, "The size under which the people of a GitHub team are not removed anymore")
+	pfs.StringVar(&o.OrgMembership, "org-membership", string(syncer.OrgMembershipIgnore), fmt.Sprintf("How to handle the people added to the GitHub teams that are not organization members, that Peribolos rejects: one of %v. With add, they are added to the organization members, with require the sync fails listing them", syncer.OrgMemberships))
+	pfs.BoolVar(&o.OverrideGuardrails, "override-guardrails", false, "Whether to apply anyway the changes beyond the guardrails, like the maximum handles added or removed and the removal of every maintainer")
+
+	o.CreateTeam.AddPFlags(pfs)
+}
+
+// Validate validates the common sync options. It possibly returns an error.
+func (o *CommonOptions) Validate() error {
+	if o.MinTeamSize < 0 {
+		return errors.New("minimum team size is negative")
+	}
+
+	if err := syncer.ValidateOrgMembership(syncer.OrgMembership(o.OrgMembership)); err != nil {
+		return err
+	}
+
+	if err := o.CreateTeam.Validate(); err != nil {
+		return err
+	}
+
+	_, err := o.Guardrails()
+
+	return err
+}
+
+// Guardrails returns the limits of the changes to every Team. It possibly returns an error.
+func (o *CommonOptions) Guardrails() (*syncer.Guardrails, error) {
+	maxAdded, err := syncer.ParseLimit(o.MaxAdded)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing maximum added handles")
+	}
+
+	maxRemoved, err := syncer.ParseLimit(o.MaxRemoved)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing maximum removed handles")
+	}
+
+	return &syncer.Guardrails{
+		MaxAdded:    maxAdded,
+		MaxRemoved:  maxRemoved,
+		MinTeamSize: o.MinTeamSize,
+		Override:    o.OverrideGuardrails,
+	}, nil
+}
+
+// LoadHandleMapping returns the handle mapping of the handle mapping file, if any. It possibly returns an error.
+func (o *CommonOptions) LoadHandleMapping() (syncer.HandleMapping, error) {
+	if o.HandleMappingFile == "" {
+		return syncer.HandleMapping{}, nil
+	}
+
+	return handles.Load(o.HandleMappingFile)
+}
+
+// Policy returns the policy of the handles never added to and never removed from any Team.
+func (o *CommonOptions) Policy() *orgs.Policy {
+	return &orgs.Policy{Deny: o.DenyHandles, Protect: o.ProtectHandles}
 }
@@ -0,0 +1,132 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peribolos
+
+import (
+	"fmt"
+	"strings"
+
+	peribolos "k8s.io/test-infra/prow/config/org"
+)
+
+// Changes represents the GitHub handles added to and removed from a list of people.
+type Changes struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Empty returns whether no handle has been added nor removed.
+func (c *Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0
+}
+
+// TeamChanges represents the changes applied to the people of a GitHub Team, with the handles that a Policy filtered
+// out of them.
+type TeamChanges struct {
+	Org         string  `json:"org"`
+	Team        string  `json:"team"`
+	Maintainers Changes `json:"maintainers"`
+	Members     Changes `json:"members"`
+
+	// Created represents whether the Team has been added to the Peribolos config.
+	Created bool `json:"created,omitempty"`
+
+	// OrgMembers represents the people of the Team added to the members of the Organization.
+	OrgMembers []string `json:"org_members,omitempty"`
+
+	Filtered
+}
+
+// Empty returns whether the Team has neither been created nor its people changed. Filtered handles are not changes.
+func (c *TeamChanges) Empty() bool {
+	return !c.Created && c.Maintainers.Empty() && c.Members.Empty()
+}
+
+// String returns the changes in a diff-like format, one handle per line, grouped by role.
+func (c *TeamChanges) String() string {
+	var b strings.Builder
+
+	if c.Created {
+		fmt.Fprintf(&b, "team:\n+ %s\n", c.Team)
+	}
+
+	for _, role := range []struct {
+		name    string
+		changes Changes
+	}{
+		{"maintainers", c.Maintainers},
+		{"members", c.Members},
+		{"org members", Changes{Added: c.OrgMembers}},
+	} {
+		if role.changes.Empty() {
+			continue
+		}
+
+		fmt.Fprintf(&b, "%s:\n", role.name)
+
+		for _, v := range role.changes.Added {
+			fmt.Fprintf(&b, "+ %s\n", v)
+		}
+
+		for _, v := range role.changes.Removed {
+			fmt.Fprintf(&b, "- %s\n", v)
+		}
+	}
+
+	return b.String()
+}
+
+// FilteredString returns the filtered handles, one kind per line, or an empty string when none has been filtered.
+func (c *TeamChanges) FilteredString() string {
+	var b strings.Builder
+
+	if len(c.Denied) > 0 {
+		fmt.Fprintf(&b, "denied: %s\n", strings.Join(c.Denied, ", "))
+	}
+
+	if len(c.Protected) > 0 {
+		fmt.Fprintf(&b, "protected: %s\n", strings.Join(c.Protected, ", "))
+	}
+
+	return b.String()
+}
+
+// DiffTeam returns the changes between two versions of the people of the specified Team.
+func DiffTeam(org, team string, before, after peribolos.Team) *TeamChanges {
+	return &TeamChanges{
+		Org:         org,
+		Team:        team,
+		Maintainers: diff(before.Maintainers, after.Maintainers),
+		Members:     diff(before.Members, after.Members),
+	}
+}
+
+func diff(before, after []string) Changes {
+	changes := Changes{}
+
+	for _, v := range after {
+		if !containsLogin(before, v) {
+			changes.Added = append(changes.Added, v)
+		}
+	}
+
+	for _, v := range before {
+		if !containsLogin(after, v) {
+			changes.Removed = append(changes.Removed, v)
+		}
+	}
+
+	return changes
+}
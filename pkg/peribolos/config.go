@@ -15,10 +15,8 @@
 package peribolos
 
 import (
-	"fmt"
 	"io"
 
-	"bitbucket.org/creachadair/stringset"
 	"github.com/go-git/go-billy/v5"
 	"github.com/pkg/errors"
 	peribolos "k8s.io/test-infra/prow/config/org"
@@ -43,9 +41,15 @@ func LoadConfigFromFilesystem(fs billy.Filesystem, configPath string) (*peribolo
 		return nil, errors.Wrap(err, "error reading peribolos config file")
 	}
 
+	return LoadConfig(b)
+}
+
+// LoadConfig loads the peribolos config from its file content.
+// It possibly returns an error.
+func LoadConfig(b []byte) (*peribolos.FullConfig, error) {
 	config := NewConfig()
 
-	if err = yaml.Unmarshal(b, config); err != nil {
+	if err := yaml.Unmarshal(b, config); err != nil {
 		return nil, errors.Wrap(err, "error unmarshaling peribolos config")
 	}
 
@@ -55,56 +59,244 @@ func LoadConfigFromFilesystem(fs billy.Filesystem, configPath string) (*peribolo
 // AddTeamMaintainers updates the maintainers of the specified Team in the specified Organization, adding the maintainers
 // list specified as agument.
 func AddTeamMaintainers(config *peribolos.FullConfig, org, team string, maintainers []string) error {
+	_, err := AddTeamMaintainersWithPolicy(config, org, team, maintainers, nil)
+
+	return err
+}
+
+// AddTeamMaintainersWithPolicy is like AddTeamMaintainers, but the policy, if any, filters the handles added and
+// removed. It returns the filtered handles.
+func AddTeamMaintainersWithPolicy(config *peribolos.FullConfig, org, team string, maintainers []string,
+	policy *Policy,
+) (*Filtered, error) {
+	return updateTeam(config, org, team, policy, func(t *peribolos.Team) {
+		t.Maintainers = with(t.Maintainers, maintainers)
+	})
+}
+
+// AddTeamMembers updates the members of the specified Team in the specified Organization, adding the members list
+// specified as argument. People already listed as maintainers are not added as members.
+func AddTeamMembers(config *peribolos.FullConfig, org, team string, members []string) error {
+	_, err := AddTeamMembersWithPolicy(config, org, team, members, nil)
+
+	return err
+}
+
+// AddTeamMembersWithPolicy is like AddTeamMembers, but the policy, if any, filters the handles added and removed.
+// It returns the filtered handles.
+func AddTeamMembersWithPolicy(config *peribolos.FullConfig, org, team string, members []string,
+	policy *Policy,
+) (*Filtered, error) {
+	return updateTeam(config, org, team, policy, func(t *peribolos.Team) {
+		t.Members = with(t.Members, without(members, t.Maintainers))
+	})
+}
+
+// ReconcileTeamMaintainers updates the maintainers of the specified Team in the specified Organization, so that they
+// exactly match the maintainers list specified as argument. Existing maintainers keep their order.
+func ReconcileTeamMaintainers(config *peribolos.FullConfig, org, team string, maintainers []string) error {
+	_, err := ReconcileTeamMaintainersWithPolicy(config, org, team, maintainers, nil)
+
+	return err
+}
+
+// ReconcileTeamMaintainersWithPolicy is like ReconcileTeamMaintainers, but the policy, if any, filters the handles
+// added and removed. It returns the filtered handles.
+func ReconcileTeamMaintainersWithPolicy(config *peribolos.FullConfig, org, team string, maintainers []string,
+	policy *Policy,
+) (*Filtered, error) {
+	return updateTeam(config, org, team, policy, func(t *peribolos.Team) {
+		t.Maintainers = reconcile(t.Maintainers, maintainers)
+	})
+}
+
+// ReconcileTeamMembers updates the members of the specified Team in the specified Organization, so that they exactly
+// match the members list specified as argument. Existing members keep their order. People already listed as
+// maintainers are not added as members.
+func ReconcileTeamMembers(config *peribolos.FullConfig, org, team string, members []string) error {
+	_, err := ReconcileTeamMembersWithPolicy(config, org, team, members, nil)
+
+	return err
+}
+
+// ReconcileTeamMembersWithPolicy is like ReconcileTeamMembers, but the policy, if any, filters the handles added and
+// removed. It returns the filtered handles.
+func ReconcileTeamMembersWithPolicy(config *peribolos.FullConfig, org, team string, members []string,
+	policy *Policy,
+) (*Filtered, error) {
+	return updateTeam(config, org, team, policy, func(t *peribolos.Team) {
+		// The maintainers already listed as members are left as they are.
+		t.Members = reconcile(t.Members, without(members, without(t.Maintainers, t.Members)))
+	})
+}
+
+// AddTeamRoles updates the people of the specified Team in the specified Organization, adding the maintainers and
+// the members lists specified as argument. People specified as both maintainers and members are added as maintainers.
+// People whose role changes are moved between the maintainers and the members lists, so that no one is listed in both.
+func AddTeamRoles(config *peribolos.FullConfig, org, team string, maintainers, members []string) error {
+	_, err := AddTeamRolesWithPolicy(config, org, team, maintainers, members, nil)
+
+	return err
+}
+
+// AddTeamRolesWithPolicy is like AddTeamRoles, but the policy, if any, filters the handles added and removed.
+// It returns the filtered handles.
+func AddTeamRolesWithPolicy(config *peribolos.FullConfig, org, team string, maintainers, members []string,
+	policy *Policy,
+) (*Filtered, error) {
+	return updateTeam(config, org, team, policy, func(t *peribolos.Team) {
+		members = without(members, maintainers)
+
+		t.Maintainers, t.Members = with(without(t.Maintainers, members), maintainers),
+			with(without(t.Members, maintainers), members)
+	})
+}
+
+// ReconcileTeamRoles updates the people of the specified Team in the specified Organization, so that they exactly
+// match the maintainers and the members lists specified as argument. People specified as both maintainers and members
+// are kept as maintainers only. Existing people keep their order.
+func ReconcileTeamRoles(config *peribolos.FullConfig, org, team string, maintainers, members []string) error {
+	_, err := ReconcileTeamRolesWithPolicy(config, org, team, maintainers, members, nil)
+
+	return err
+}
+
+// ReconcileTeamRolesWithPolicy is like ReconcileTeamRoles, but the policy, if any, filters the handles added and
+// removed. It returns the filtered handles.
+func ReconcileTeamRolesWithPolicy(config *peribolos.FullConfig, org, team string, maintainers, members []string,
+	policy *Policy,
+) (*Filtered, error) {
+	return updateTeam(config, org, team, policy, func(t *peribolos.Team) {
+		t.Maintainers = reconcile(t.Maintainers, maintainers)
+		t.Members = reconcile(t.Members, without(members, maintainers))
+	})
+}
+
+// updateTeam applies the specified update to the people of the specified Team in the specified Organization, then
+// enforces the policy, if any. It returns the filtered handles. It possibly returns an error.
+func updateTeam(config *peribolos.FullConfig, org, team string, policy *Policy,
+	update func(t *peribolos.Team),
+) (*Filtered, error) {
+	before, err := GetTeam(config, org, team)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := GetTeam(config, org, team)
+	if err != nil {
+		return nil, err
+	}
+
+	update(&after)
+
+	after, filtered := policy.enforce(before, after)
+
+	ref, err := ResolveTeam(config, org, team)
+	if err != nil {
+		return nil, err
+	}
+
+	storeTeam(config, org, ref, after)
+
+	return filtered, nil
+}
+
+// MissingOrgMembers returns the specified people that are neither members nor admins of the specified Organization.
+// None is missing when the Organization declares neither members nor admins, as its membership is not managed by the
+// config. It possibly returns an error.
+func MissingOrgMembers(config *peribolos.FullConfig, org string, people []string) ([]string, error) {
 	orgConfig, ok := config.Orgs[org]
 	if !ok {
-		//nolint:goerr113
-		return fmt.Errorf("organization not found in peribolos config")
+		return nil, errors.New("organization not found in peribolos config")
 	}
 
-	teamConfig, ok := orgConfig.Teams[team]
-	if !ok {
-		//nolint:goerr113
-		return fmt.Errorf("team not fonud in organization %s peribolos config", org)
+	if !managesMembership(&orgConfig) {
+		return []string{}, nil
 	}
 
-	m := teamConfig.Maintainers
-	for _, v := range maintainers {
-		if !stringset.Contains(m, v) {
-			m = append(m, v)
-		}
+	return without(without(people, orgConfig.Members), orgConfig.Admins), nil
+}
+
+// managesMembership returns whether the Organization membership is managed by the config, that is whether it declares
+// members or admins.
+func managesMembership(orgConfig *peribolos.Config) bool {
+	return len(orgConfig.Members) > 0 || len(orgConfig.Admins) > 0
+}
+
+// AddOrgMembers adds the specified people that are neither members nor admins of the specified Organization to its
+// members, unless its membership is not managed by the config. It returns the added people. It possibly returns an
+// error.
+func AddOrgMembers(config *peribolos.FullConfig, org string, people []string) ([]string, error) {
+	missing, err := MissingOrgMembers(config, org, people)
+	if err != nil {
+		return nil, err
 	}
 
-	teamConfig.Maintainers = m
-	orgConfig.Teams[team] = teamConfig
+	orgConfig := config.Orgs[org]
+	orgConfig.Members = with(orgConfig.Members, missing)
 	config.Orgs[org] = orgConfig
 
-	return nil
+	return missing, nil
 }
 
-// AddTeamMembers updates the members of the specified Team in the specified Organization, adding the members list
-// specified as argument.
-func AddTeamMembers(config *peribolos.FullConfig, org, team string, members []string) error {
-	orgConfig, ok := config.Orgs[org]
-	if !ok {
-		return errors.New("organization not found in peribolos config")
+// GetTeam returns a copy of the people of the specified Team in the specified Organization. The Team is looked up
+// like ResolveTeam does. It possibly returns an error.
+func GetTeam(config *peribolos.FullConfig, org, team string) (peribolos.Team, error) {
+	ref, err := ResolveTeam(config, org, team)
+	if err != nil {
+		return peribolos.Team{}, err
 	}
 
-	teamConfig, ok := orgConfig.Teams[team]
-	if !ok {
-		//nolint:goerr113
-		return fmt.Errorf("team not found in organization %s peribolos config", org)
+	teamConfig, _ := lookupTeam(config.Orgs[org], ref)
+
+	teamConfig.Members = append([]string{}, teamConfig.Members...)
+	teamConfig.Maintainers = append([]string{}, teamConfig.Maintainers...)
+
+	return teamConfig, nil
+}
+
+// reconcile returns the current people that are still desired, followed by the desired people that are not in the
+// current list yet.
+func reconcile(current, desired []string) []string {
+	result := []string{}
+
+	for _, v := range current {
+		if containsLogin(desired, v) && !containsLogin(result, v) {
+			result = append(result, v)
+		}
 	}
 
-	m := teamConfig.Members
-	for _, v := range members {
-		if !stringset.Contains(m, v) {
-			m = append(m, v)
+	for _, v := range desired {
+		if !containsLogin(result, v) {
+			result = append(result, v)
 		}
 	}
 
-	teamConfig.Members = m
-	orgConfig.Teams[team] = teamConfig
-	config.Orgs[org] = orgConfig
+	return result
+}
+
+// without returns the people of the list that are not in the excluded list.
+func without(list, excluded []string) []string {
+	result := []string{}
+
+	for _, v := range list {
+		if !containsLogin(excluded, v) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// with returns the list followed by the added people that are not in the list yet.
+func with(list, added []string) []string {
+	result := append([]string{}, list...)
+
+	for _, v := range added {
+		if !containsLogin(result, v) {
+			result = append(result, v)
+		}
+	}
 
-	return nil
+	return result
 }
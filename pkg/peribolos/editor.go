@@ -0,0 +1,567 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peribolos
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+	peribolos "k8s.io/test-infra/prow/config/org"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+const (
+	keyOrgs        = "orgs"
+	keyTeams       = "teams"
+	keyMembers     = "members"
+	keyMaintainers = "maintainers"
+)
+
+// ConfigEditor edits the people lists of a Peribolos config file. Only the lines of the edited lists are rewritten:
+// comments, keys order, indentation and quoting of everything else are preserved byte by byte.
+type ConfigEditor struct {
+	lines []string
+	root  *yaml.Node
+}
+
+// NewConfigEditor returns a new ConfigEditor for the specified Peribolos config file content.
+// It possibly returns an error.
+func NewConfigEditor(src []byte) (*ConfigEditor, error) {
+	e := &ConfigEditor{}
+
+	if err := e.parse(strings.Split(string(src), "\n")); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Bytes returns the edited Peribolos config file content.
+func (e *ConfigEditor) Bytes() []byte {
+	return []byte(strings.Join(e.lines, "\n"))
+}
+
+// SetTeamMaintainers replaces the maintainers list of the specified Team in the specified Organization.
+// It possibly returns an error.
+func (e *ConfigEditor) SetTeamMaintainers(org, team string, maintainers []string) error {
+	return e.setList(teamPath(org, team), keyMaintainers, maintainers)
+}
+
+// SetTeamMembers replaces the members list of the specified Team in the specified Organization.
+// It possibly returns an error.
+func (e *ConfigEditor) SetTeamMembers(org, team string, members []string) error {
+	return e.setList(teamPath(org, team), keyMembers, members)
+}
+
+// AddTeam appends the specified Team, with its metadata and people, to the Teams of the specified Organization or,
+// for a child Team, of its parent Team. It possibly returns an error.
+func (e *ConfigEditor) AddTeam(org, team string, t peribolos.Team) error {
+	parentRef, name := splitTeam(team)
+
+	path := []string{keyOrgs, org}
+	if parentRef != "" {
+		path = teamPath(org, parentRef)
+	}
+
+	parent, err := e.mapping(path)
+	if err != nil {
+		return err
+	}
+
+	k, v := lookup(parent, keyTeams)
+
+	var lines []string
+
+	switch {
+	case k == nil:
+		indent := strings.Repeat(" ", parent.Content[0].Column-1)
+		end := e.endLine(parent)
+		lines = splice(e.lines, end, end, append([]string{indent + keyTeams + ":"},
+			e.teamLines(indent+e.indentStep(), name, t)...))
+	case (v.Kind == yaml.ScalarNode && v.Tag == "!!null") || (v.Kind == yaml.MappingNode && len(v.Content) == 0):
+		head, comment := e.splitKeyLine(k)
+		indent := e.lines[k.Line-1][:k.Column-1]
+		lines = splice(e.lines, k.Line-1, k.Line, append([]string{head + comment},
+			e.teamLines(indent+e.indentStep(), name, t)...))
+	case v.Kind != yaml.MappingNode || v.Style&yaml.FlowStyle != 0:
+		//nolint:goerr113
+		return fmt.Errorf("%s.%s is not a block mapping in peribolos config", strings.Join(path, "."), keyTeams)
+	default:
+		end := e.endLine(v)
+		lines = splice(e.lines, end, end, e.teamLines(strings.Repeat(" ", v.Content[0].Column-1), name, t))
+	}
+
+	return e.parse(lines)
+}
+
+// SetOrgMembers replaces the members list of the specified Organization. It possibly returns an error.
+func (e *ConfigEditor) SetOrgMembers(org string, members []string) error {
+	return e.setList([]string{keyOrgs, org}, keyMembers, members)
+}
+
+// PatchConfig returns the specified Peribolos config file content, updated to reflect the members of the
+// Organizations and the people of the Teams in the specified config, nested Teams included. Only the people lists
+// that differ from the original ones are rewritten, and the Teams that are not in the original config are appended.
+// It possibly returns an error.
+func PatchConfig(src []byte, config *peribolos.FullConfig) ([]byte, error) {
+	original := NewConfig()
+	if err := sigsyaml.Unmarshal(src, original); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling the original peribolos config")
+	}
+
+	editor, err := NewConfigEditor(src)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, org := range sortedKeys(config.Orgs) {
+		originalOrg, ok := original.Orgs[org]
+		if !ok {
+			//nolint:goerr113
+			return nil, fmt.Errorf("organization %s not found in the original peribolos config", org)
+		}
+
+		if !slices.Equal(originalOrg.Members, config.Orgs[org].Members) {
+			if err = editor.SetOrgMembers(org, config.Orgs[org].Members); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = editor.patchTeams(org, "", originalOrg.Teams, config.Orgs[org].Teams); err != nil {
+			return nil, err
+		}
+	}
+
+	return editor.Bytes(), nil
+}
+
+// patchTeams rewrites the people lists of the specified Teams, children of the specified parent Team if any, that
+// differ from the original ones, and appends the Teams that are not in the original config.
+func (e *ConfigEditor) patchTeams(org, parent string, original, teams map[string]peribolos.Team) error {
+	for _, name := range sortedKeys(teams) {
+		team, ref := teams[name], joinTeam(parent, name)
+
+		originalTeam, ok := original[name]
+		if !ok {
+			if err := e.AddTeam(org, ref, team); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !slices.Equal(originalTeam.Maintainers, team.Maintainers) {
+			if err := e.SetTeamMaintainers(org, ref, team.Maintainers); err != nil {
+				return err
+			}
+		}
+
+		if !slices.Equal(originalTeam.Members, team.Members) {
+			if err := e.SetTeamMembers(org, ref, team.Members); err != nil {
+				return err
+			}
+		}
+
+		if err := e.patchTeams(org, ref, originalTeam.Children, team.Children); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *ConfigEditor) parse(lines []string) error {
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal([]byte(strings.Join(lines, "\n")), root); err != nil {
+		return errors.Wrap(err, "error parsing peribolos config")
+	}
+
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return errors.New("peribolos config is not a yaml mapping")
+	}
+
+	e.lines = lines
+	e.root = root.Content[0]
+
+	return nil
+}
+
+// mapping returns the block mapping found at the specified path. It possibly returns an error.
+func (e *ConfigEditor) mapping(path []string) (*yaml.Node, error) {
+	node := e.root
+
+	for _, k := range path {
+		_, v := lookup(node, k)
+		if v == nil {
+			//nolint:goerr113
+			return nil, fmt.Errorf("%s not found in peribolos config", strings.Join(path, "."))
+		}
+
+		node = v
+	}
+
+	if node.Kind != yaml.MappingNode || node.Style&yaml.FlowStyle != 0 || len(node.Content) == 0 {
+		//nolint:goerr113
+		return nil, fmt.Errorf("%s is not a block mapping in peribolos config", strings.Join(path, "."))
+	}
+
+	return node, nil
+}
+
+// setList replaces the list at the specified key of the mapping found at the specified path.
+func (e *ConfigEditor) setList(path []string, key string, values []string) error {
+	parent, err := e.mapping(path)
+	if err != nil {
+		return err
+	}
+
+	k, v := lookup(parent, key)
+
+	var lines []string
+
+	switch {
+	case k == nil:
+		if len(values) == 0 {
+			return nil
+		}
+
+		lines = e.insertKey(parent, key, values)
+	case v.Kind == yaml.ScalarNode && v.Tag == "!!null":
+		lines = e.replaceEmpty(k, v, values)
+	case v.Kind != yaml.SequenceNode:
+		//nolint:goerr113
+		return fmt.Errorf("%s.%s is not a list in peribolos config", strings.Join(path, "."), key)
+	case v.Style&yaml.FlowStyle != 0 && len(v.Content) == 0:
+		lines = e.replaceEmpty(k, v, values)
+	case v.Style&yaml.FlowStyle != 0:
+		l, err := e.replaceFlow(v, values)
+		if err != nil {
+			return err
+		}
+
+		lines = l
+	default:
+		lines = e.replaceBlock(k, v, values)
+	}
+
+	return e.parse(lines)
+}
+
+// insertKey returns the lines with a new block list appended at the end of the specified mapping.
+func (e *ConfigEditor) insertKey(mapping *yaml.Node, key string, values []string) []string {
+	indent := strings.Repeat(" ", mapping.Content[0].Column-1)
+	end := e.endLine(mapping)
+
+	added := []string{indent + key + ":"}
+	added = append(added, e.blockItems(indent, values)...)
+
+	return splice(e.lines, end, end, added)
+}
+
+// replaceEmpty returns the lines with the empty or null value of the specified key replaced by a block list.
+func (e *ConfigEditor) replaceEmpty(key, value *yaml.Node, values []string) []string {
+	if len(values) == 0 {
+		return e.lines
+	}
+
+	head, comment := e.splitKeyLine(key)
+	indent := e.lines[key.Line-1][:key.Column-1]
+
+	// An explicit value could be on the lines following the key.
+	end := key.Line
+	if value.Value != "" || value.Kind == yaml.SequenceNode {
+		end = value.Line
+	}
+
+	replaced := append([]string{head + comment}, e.blockItems(indent, values)...)
+
+	return splice(e.lines, key.Line-1, end, replaced)
+}
+
+// replaceFlow returns the lines with the single-line flow list replaced by the specified values.
+func (e *ConfigEditor) replaceFlow(value *yaml.Node, values []string) ([]string, error) {
+	line := e.lines[value.Line-1]
+	start := value.Column - 1
+
+	end := strings.Index(line[start:], "]")
+	if end < 0 {
+		//nolint:goerr113
+		return nil, fmt.Errorf("unsupported multi-line flow list at line %d of peribolos config", value.Line)
+	}
+
+	items := make([]string, 0, len(values))
+	for _, v := range values {
+		items = append(items, formatScalar(v, true))
+	}
+
+	replaced := line[:start] + "[" + strings.Join(items, ", ") + "]" + line[start+end+1:]
+
+	return splice(e.lines, value.Line-1, value.Line, []string{replaced}), nil
+}
+
+// replaceBlock returns the lines with the items of the block list replaced by the specified values. The lines of the
+// items that are kept are preserved along with the comments preceding them.
+func (e *ConfigEditor) replaceBlock(key, value *yaml.Node, values []string) []string {
+	first := value.Content[0]
+	last := value.Content[len(value.Content)-1]
+
+	if len(values) == 0 {
+		head, comment := e.splitKeyLine(key)
+
+		return splice(splice(e.lines, first.Line-1, last.Line, nil), key.Line-1, key.Line, []string{head + " []" + comment})
+	}
+
+	prefix := e.lines[first.Line-1][:first.Column-1]
+
+	// Map each existing item to its lines, including the comments preceding it.
+	chunks := map[string][]string{}
+	start := first.Line - 1
+
+	for _, item := range value.Content {
+		if _, ok := chunks[item.Value]; !ok && item.Kind == yaml.ScalarNode {
+			chunks[item.Value] = e.lines[start:item.Line]
+		}
+
+		start = item.Line
+	}
+
+	replaced := []string{}
+
+	for _, v := range values {
+		if chunk, ok := chunks[v]; ok {
+			replaced = append(replaced, chunk...)
+
+			continue
+		}
+
+		replaced = append(replaced, prefix+formatScalar(v, false))
+	}
+
+	return splice(e.lines, first.Line-1, last.Line, replaced)
+}
+
+// teamLines returns the lines of the specified Team as a mapping entry with the specified indentation, with its keys
+// sorted like Peribolos dumps them.
+func (e *ConfigEditor) teamLines(indent, name string, t peribolos.Team) []string {
+	step := e.indentStep()
+	lines := []string{indent + formatScalar(name, false) + ":"}
+
+	if t.Description != nil {
+		lines = append(lines, indent+step+"description: "+formatScalar(*t.Description, false))
+	}
+
+	for _, list := range []struct {
+		key    string
+		values []string
+	}{
+		{keyMaintainers, t.Maintainers},
+		{keyMembers, t.Members},
+	} {
+		if len(list.values) > 0 {
+			lines = append(lines, indent+step+list.key+":")
+			lines = append(lines, e.blockItems(indent+step, list.values)...)
+		}
+	}
+
+	if t.Privacy != nil {
+		lines = append(lines, indent+step+"privacy: "+formatScalar(string(*t.Privacy), false))
+	}
+
+	if len(t.Children) > 0 {
+		lines = append(lines, indent+step+keyTeams+":")
+
+		for _, child := range sortedKeys(t.Children) {
+			lines = append(lines, e.teamLines(indent+step+step, child, t.Children[child])...)
+		}
+	}
+
+	if len(lines) == 1 {
+		lines[0] += " {}"
+	}
+
+	return lines
+}
+
+// indentStep returns the indentation of the nested mappings of the config, the one of the organizations.
+func (e *ConfigEditor) indentStep() string {
+	if k, v := lookup(e.root, keyOrgs); v != nil && v.Kind == yaml.MappingNode && len(v.Content) > 0 &&
+		v.Content[0].Column > k.Column {
+		return strings.Repeat(" ", v.Content[0].Column-k.Column)
+	}
+
+	return "  "
+}
+
+// blockItems returns the lines of a block list of the specified values, for a key with the specified indentation.
+// The indentation of the items follows the one of the first block list in the config.
+func (e *ConfigEditor) blockItems(indent string, values []string) []string {
+	prefix := indent + "- "
+
+	if k, v := findBlockList(e.root); v != nil {
+		line := e.lines[v.Content[0].Line-1]
+		offset := v.Content[0].Column - k.Column
+
+		if offset >= 2 && strings.TrimSpace(line[:v.Content[0].Column-1]) == "-" {
+			prefix = indent + strings.Repeat(" ", offset-2) + "- "
+		}
+	}
+
+	items := make([]string, 0, len(values))
+	for _, v := range values {
+		items = append(items, prefix+formatScalar(v, false))
+	}
+
+	return items
+}
+
+// splitKeyLine returns the line of the specified key until its colon, and the trailing comment of the line if any.
+func (e *ConfigEditor) splitKeyLine(key *yaml.Node) (string, string) {
+	line := e.lines[key.Line-1]
+
+	colon := key.Column - 1 + len(key.Value)
+	if i := strings.Index(line[colon:], ":"); i >= 0 {
+		colon += i
+	}
+
+	head := line[:colon+1]
+
+	comment := ""
+	if i := strings.Index(line[colon+1:], "#"); i >= 0 {
+		comment = " " + line[colon+1+i:]
+	}
+
+	return head, comment
+}
+
+// endLine returns the index of the line following the last line of the specified node, excluding trailing blank lines.
+func (e *ConfigEditor) endLine(node *yaml.Node) int {
+	end := lastLine(node)
+	indent := node.Column - 1
+
+	// Include the continuation lines of multi-line scalars.
+	for end < len(e.lines) {
+		line := e.lines[end]
+		trimmed := strings.TrimLeft(line, " ")
+
+		if trimmed != "" && len(line)-len(trimmed) <= indent {
+			break
+		}
+
+		end++
+	}
+
+	for end > 0 && strings.TrimSpace(e.lines[end-1]) == "" {
+		end--
+	}
+
+	return end
+}
+
+// lookup returns the key and the value nodes of the specified key in the specified mapping node.
+func lookup(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
+
+// findBlockList returns the key and the value nodes of the first non-empty block list found in the specified node.
+func findBlockList(node *yaml.Node) (*yaml.Node, *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k, v := node.Content[i], node.Content[i+1]
+
+		if v.Kind == yaml.SequenceNode && v.Style&yaml.FlowStyle == 0 && len(v.Content) > 0 {
+			return k, v
+		}
+
+		if fk, fv := findBlockList(v); fv != nil {
+			return fk, fv
+		}
+	}
+
+	return nil, nil
+}
+
+// lastLine returns the greatest line number of the specified node and its descendants.
+func lastLine(node *yaml.Node) int {
+	last := node.Line
+
+	for _, child := range node.Content {
+		if l := lastLine(child); l > last {
+			last = l
+		}
+	}
+
+	return last
+}
+
+// formatScalar returns the specified value as a yaml scalar, quoted only when needed.
+func formatScalar(value string, flow bool) string {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if flow && strings.ContainsAny(value, "[]{},") {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+
+	b, err := yaml.Marshal(node)
+	if err != nil {
+		return value
+	}
+
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+// splice returns the lines with the lines in the range [start, end) replaced by the specified ones.
+func splice(lines []string, start, end int, replaced []string) []string {
+	result := make([]string, 0, len(lines)-(end-start)+len(replaced))
+	result = append(result, lines[:start]...)
+	result = append(result, replaced...)
+	result = append(result, lines[end:]...)
+
+	return result
+}
+
+// teamPath returns the path of the mapping of the specified Team, nested Teams included.
+func teamPath(org, team string) []string {
+	path := []string{keyOrgs, org}
+
+	for _, name := range strings.Split(team, TeamSeparator) {
+		path = append(path, keyTeams, name)
+	}
+
+	return path
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	sort.Strings(keys)
+
+	return keys
+}
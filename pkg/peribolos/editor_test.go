@@ -0,0 +1,269 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peribolos_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	peribolos "k8s.io/test-infra/prow/config/org"
+	"sigs.k8s.io/yaml"
+
+	. "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+const editedConfig = `# The acme organization.
+orgs:
+  acme:
+    admins:
+      - alice
+    members:
+      - alice
+      - bob # The second member.
+    teams:
+      admins:
+        # The team description.
+        description: The admins.
+        maintainers: [alice]
+        members:
+          - alice
+          # Bob is here.
+          - bob
+        privacy: closed
+      empty:
+        description: |
+          An empty team,
+          with a long description.
+      nulled:
+        members: # To be filled.
+        privacy: secret
+`
+
+var _ = Describe("Patching Peribolos config", func() {
+	var (
+		err     error
+		config  *peribolos.FullConfig
+		patched []byte
+	)
+
+	BeforeEach(func() {
+		config = NewConfig()
+		Expect(yaml.Unmarshal([]byte(editedConfig), config)).To(Succeed())
+	})
+
+	Context("the config has not changed", func() {
+		BeforeEach(func() {
+			patched, err = PatchConfig([]byte(editedConfig), config)
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should preserve the content", func() {
+			Expect(string(patched)).To(Equal(editedConfig))
+		})
+	})
+
+	Context("the block list members have changed", func() {
+		BeforeEach(func() {
+			Expect(ReconcileTeamMembers(config, org, team, []string{"bob", "charlie"})).To(Succeed())
+			patched, err = PatchConfig([]byte(editedConfig), config)
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should only change the list items, preserving the comments of the kept ones", func() {
+			Expect(string(patched)).To(ContainSubstring(`        members:
+          # Bob is here.
+          - bob
+          - charlie
+        privacy: closed
+`))
+		})
+
+		It("should preserve the rest of the content", func() {
+			Expect(string(patched)).To(HavePrefix(`# The acme organization.
+orgs:
+  acme:
+    admins:
+      - alice
+    members:
+      - alice
+      - bob # The second member.
+    teams:
+      admins:
+        # The team description.
+        description: The admins.
+        maintainers: [alice]
+`))
+		})
+	})
+
+	Context("the flow list maintainers have changed", func() {
+		BeforeEach(func() {
+			Expect(AddTeamMaintainers(config, org, team, []string{"bob"})).To(Succeed())
+			patched, err = PatchConfig([]byte(editedConfig), config)
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should keep the flow style", func() {
+			Expect(string(patched)).To(ContainSubstring("        maintainers: [alice, bob]\n"))
+		})
+	})
+
+	Context("the list is missing", func() {
+		BeforeEach(func() {
+			Expect(AddTeamMembers(config, org, "empty", []string{"charlie"})).To(Succeed())
+			patched, err = PatchConfig([]byte(editedConfig), config)
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should append the list at the end of the team, with the indentation of the other lists", func() {
+			Expect(string(patched)).To(ContainSubstring(`      empty:
+        description: |
+          An empty team,
+          with a long description.
+        members:
+          - charlie
+      nulled:
+`))
+		})
+	})
+
+	Context("the list is null", func() {
+		BeforeEach(func() {
+			Expect(AddTeamMembers(config, org, "nulled", []string{"charlie"})).To(Succeed())
+			patched, err = PatchConfig([]byte(editedConfig), config)
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should fill the list, preserving the comment", func() {
+			Expect(string(patched)).To(ContainSubstring(`      nulled:
+        members: # To be filled.
+          - charlie
+        privacy: secret
+`))
+		})
+	})
+
+	Context("the list is emptied", func() {
+		BeforeEach(func() {
+			Expect(ReconcileTeamMembers(config, org, team, nil)).To(Succeed())
+			patched, err = PatchConfig([]byte(editedConfig), config)
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should replace the list with an empty one", func() {
+			Expect(string(patched)).To(ContainSubstring(`        members: []
+        privacy: closed
+`))
+		})
+
+		It("should produce a valid config", func() {
+			patchedConfig := NewConfig()
+			Expect(yaml.Unmarshal(patched, patchedConfig)).To(Succeed())
+			Expect(patchedConfig.Orgs[org].Teams[team].Members).To(BeEmpty())
+			Expect(patchedConfig.Orgs[org].Teams[team].Maintainers).To(Equal([]string{admin}))
+		})
+	})
+
+	Context("the organization members are added", func() {
+		BeforeEach(func() {
+			Expect(AddOrgMembers(config, org, []string{"Alice", "charlie"})).To(Equal([]string{"charlie"}))
+			patched, err = PatchConfig([]byte(editedConfig), config)
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should append them to the organization members", func() {
+			Expect(string(patched)).To(ContainSubstring(`    members:
+      - alice
+      - bob # The second member.
+      - charlie
+    teams:
+`))
+		})
+	})
+
+	Context("the team does not exist in the original config", func() {
+		BeforeEach(func() {
+			description, privacy := "The new team.", peribolos.Secret
+			Expect(CreateTeam(config, org, "new", peribolos.TeamMetadata{
+				Description: &description,
+				Privacy:     &privacy,
+			})).To(Succeed())
+			Expect(AddTeamMembers(config, org, "new", []string{"charlie"})).To(Succeed())
+			patched, err = PatchConfig([]byte(editedConfig), config)
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should append the team at the end of the teams, with the indentation of the other teams", func() {
+			Expect(string(patched)).To(HaveSuffix(`        privacy: secret
+      new:
+        description: The new team.
+        members:
+          - charlie
+        privacy: secret
+`))
+		})
+	})
+
+	Context("the child team does not exist in the original config", func() {
+		BeforeEach(func() {
+			Expect(CreateTeam(config, org, "admins/oncall", peribolos.TeamMetadata{})).To(Succeed())
+			Expect(AddTeamMaintainers(config, org, "admins/oncall", []string{admin})).To(Succeed())
+			patched, err = PatchConfig([]byte(editedConfig), config)
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should add the teams of the parent team", func() {
+			Expect(string(patched)).To(ContainSubstring(`        privacy: closed
+        teams:
+          oncall:
+            maintainers:
+              - alice
+      empty:
+`))
+		})
+
+		It("should produce a valid config", func() {
+			patchedConfig := NewConfig()
+			Expect(yaml.Unmarshal(patched, patchedConfig)).To(Succeed())
+			Expect(patchedConfig.Orgs[org].Teams[team].Children["oncall"].Maintainers).To(Equal([]string{admin}))
+		})
+	})
+})
@@ -0,0 +1,43 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peribolos
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxLoginLength represents the maximum length of the GitHub logins.
+const maxLoginLength = 39
+
+// loginRegexp matches the characters of the GitHub logins: alphanumeric characters or single hyphens, not at the edges.
+var loginRegexp = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9]|-[a-zA-Z0-9])*$`)
+
+// IsValidLogin returns whether the specified string is a valid GitHub login.
+func IsValidLogin(login string) bool {
+	return len(login) <= maxLoginLength && loginRegexp.MatchString(login)
+}
+
+// containsLogin returns whether the list contains the specified GitHub login. As GitHub logins are case-insensitive,
+// so is the comparison.
+func containsLogin(list []string, login string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, login) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -157,6 +157,22 @@ var _ = Describe("Updating Team's members", func() {
 			})
 		})
 
+		Context("the members are already maintainers", func() {
+			BeforeEach(func() {
+				config.Orgs[org].Teams["maintained"] = peribolos.Team{
+					Members:     []string{"bob"},
+					Maintainers: []string{"alice"},
+				}
+				err = AddTeamMembers(config, org, "maintained", []string{"alice", "charlie"})
+			})
+
+			It("should not add them as members", func() {
+				Expect(err).To(Succeed())
+				Expect(config.Orgs[org].Teams["maintained"].Members).To(Equal([]string{"bob", "charlie"}))
+				Expect(config.Orgs[org].Teams["maintained"].Maintainers).To(Equal([]string{"alice"}))
+			})
+		})
+
 		Context("the team does not exist", func() {
 			BeforeEach(func() {
 				err = AddTeamMembers(config, org, "nonexistent", []string{"charlie"})
@@ -261,3 +277,424 @@ var _ = Describe("Updating Team's maintainers", func() {
 		})
 	})
 })
+
+var _ = Describe("Reconciling Team's members", func() {
+	var (
+		err    error
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{}}
+	)
+
+	BeforeEach(func() {
+		config.Orgs = map[string]peribolos.Config{
+			org: {
+				Teams: map[string]peribolos.Team{
+					team: {
+						Members:     []string{"alice", "bob"},
+						Maintainers: []string{"alice"},
+					},
+				},
+			},
+		}
+	})
+
+	Context("the team exists", func() {
+		BeforeEach(func() {
+			err = ReconcileTeamMembers(config, org, team, []string{"charlie", "alice"})
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should exactly match the specified members", func() {
+			Expect(config.Orgs[org].Teams[team].Members).To(Equal([]string{admin, "charlie"}))
+		})
+
+		It("should not change the maintainers", func() {
+			Expect(config.Orgs[org].Teams[team].Maintainers).To(Equal([]string{admin}))
+		})
+	})
+
+	Context("the members are already maintainers", func() {
+		BeforeEach(func() {
+			config.Orgs[org].Teams["maintained"] = peribolos.Team{
+				Members:     []string{"bob"},
+				Maintainers: []string{"alice"},
+			}
+			err = ReconcileTeamMembers(config, org, "maintained", []string{"alice", "charlie"})
+		})
+
+		It("should not add them as members", func() {
+			Expect(err).To(Succeed())
+			Expect(config.Orgs[org].Teams["maintained"].Members).To(Equal([]string{"charlie"}))
+			Expect(config.Orgs[org].Teams["maintained"].Maintainers).To(Equal([]string{"alice"}))
+		})
+	})
+
+	Context("the team does not exist", func() {
+		BeforeEach(func() {
+			err = ReconcileTeamMembers(config, org, "nonexistent", []string{"charlie"})
+		})
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Comparing Team's people", func() {
+	var (
+		err    error
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{}}
+	)
+
+	BeforeEach(func() {
+		config.Orgs = map[string]peribolos.Config{
+			org: {Teams: map[string]peribolos.Team{team: {Members: []string{"Alice", "bob"}}}},
+		}
+	})
+
+	Context("when adding people with a different casing", func() {
+		BeforeEach(func() {
+			err = AddTeamMembers(config, org, team, []string{"alice", "BOB"})
+		})
+
+		It("should not add them twice", func() {
+			Expect(err).To(Succeed())
+			Expect(config.Orgs[org].Teams[team].Members).To(Equal([]string{"Alice", "bob"}))
+		})
+	})
+
+	Context("when reconciling people with a different casing", func() {
+		BeforeEach(func() {
+			err = ReconcileTeamMembers(config, org, team, []string{"alice"})
+		})
+
+		It("should keep the existing people as they are", func() {
+			Expect(err).To(Succeed())
+			Expect(config.Orgs[org].Teams[team].Members).To(Equal([]string{"Alice"}))
+		})
+	})
+
+	It("should not diff people with a different casing", func() {
+		changes := DiffTeam(org, team, peribolos.Team{Members: []string{"Alice"}}, peribolos.Team{Members: []string{"alice"}})
+		Expect(changes.Empty()).To(BeTrue())
+	})
+})
+
+var _ = Describe("Checking Organization's members", func() {
+	var config *peribolos.FullConfig
+
+	BeforeEach(func() {
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{
+			org: {Admins: []string{admin}, Members: []string{member}},
+		}}
+	})
+
+	It("should consider both admins and members", func() {
+		Expect(MissingOrgMembers(config, org, []string{"ALICE", "bob", "charlie"})).To(Equal([]string{"charlie"}))
+	})
+
+	It("should add the missing people to the members", func() {
+		Expect(AddOrgMembers(config, org, []string{"alice", "charlie"})).To(Equal([]string{"charlie"}))
+		Expect(config.Orgs[org].Members).To(Equal([]string{member, "charlie"}))
+		Expect(config.Orgs[org].Admins).To(Equal([]string{admin}))
+	})
+
+	Context("when the org membership is not managed by the config", func() {
+		BeforeEach(func() {
+			config.Orgs[org] = peribolos.Config{}
+		})
+
+		It("should not miss anyone", func() {
+			Expect(MissingOrgMembers(config, org, []string{"charlie"})).To(BeEmpty())
+		})
+
+		It("should not add anyone to the members", func() {
+			Expect(AddOrgMembers(config, org, []string{"charlie"})).To(BeEmpty())
+			Expect(config.Orgs[org].Members).To(BeEmpty())
+		})
+	})
+
+	It("should fail when the org does not exist", func() {
+		_, err := MissingOrgMembers(config, "unknown", []string{"charlie"})
+		Expect(err).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("Creating Team", func() {
+	var config *peribolos.FullConfig
+
+	BeforeEach(func() {
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{
+			org: {Teams: map[string]peribolos.Team{team: {Members: []string{member}}}},
+		}}
+	})
+
+	It("should add an empty team with its metadata", func() {
+		privacy := peribolos.Closed
+		Expect(CreateTeam(config, org, "new", peribolos.TeamMetadata{Privacy: &privacy})).To(Succeed())
+		Expect(config.Orgs[org].Teams["new"]).To(Equal(peribolos.Team{TeamMetadata: peribolos.TeamMetadata{Privacy: &privacy}}))
+	})
+
+	It("should add a child team under its parent", func() {
+		Expect(CreateTeam(config, org, team+"/child", peribolos.TeamMetadata{})).To(Succeed())
+		Expect(AddTeamMembers(config, org, team+"/child", []string{"charlie"})).To(Succeed())
+		Expect(config.Orgs[org].Teams[team].Children["child"].Members).To(Equal([]string{"charlie"}))
+		Expect(config.Orgs[org].Teams[team].Members).To(Equal([]string{member}))
+	})
+
+	It("should fail when the team already exists", func() {
+		Expect(CreateTeam(config, org, team, peribolos.TeamMetadata{})).ToNot(Succeed())
+	})
+
+	It("should fail when the parent team does not exist", func() {
+		Expect(CreateTeam(config, org, "unknown/child", peribolos.TeamMetadata{})).To(MatchError(ContainSubstring("parent team unknown")))
+	})
+})
+
+var _ = Describe("Addressing nested Teams", func() {
+	var config *peribolos.FullConfig
+
+	BeforeEach(func() {
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{
+			org: {Teams: map[string]peribolos.Team{
+				team: {Members: []string{admin}, Children: map[string]peribolos.Team{
+					"oncall": {Members: []string{member}, Children: map[string]peribolos.Team{
+						"primary": {},
+					}},
+				}},
+				"app": {Children: map[string]peribolos.Team{"ui": {}, "primary": {}}},
+			}},
+		}}
+	})
+
+	It("should list every team", func() {
+		Expect(ListTeams(config, org)).To(Equal([]string{
+			"admins", "admins/oncall", "admins/oncall/primary", "app", "app/primary", "app/ui",
+		}))
+	})
+
+	It("should resolve the references of the child teams", func() {
+		Expect(ResolveTeam(config, org, "admins/oncall")).To(Equal("admins/oncall"))
+		Expect(ResolveTeam(config, org, "oncall")).To(Equal("admins/oncall"))
+		Expect(ResolveTeam(config, org, "app")).To(Equal("app"))
+	})
+
+	It("should fail on unknown and ambiguous teams", func() {
+		_, err := ResolveTeam(config, org, "app/oncall")
+		Expect(err).To(MatchError(ContainSubstring("team not found")))
+
+		_, err = ResolveTeam(config, org, "primary")
+		Expect(err).To(MatchError(ContainSubstring("[admins/oncall/primary app/primary]")))
+	})
+
+	It("should update the nested team only", func() {
+		Expect(AddTeamMembers(config, org, "oncall", []string{"charlie"})).To(Succeed())
+		Expect(AddTeamMaintainers(config, org, "app/ui", []string{"charlie"})).To(Succeed())
+
+		Expect(config.Orgs[org].Teams[team].Members).To(Equal([]string{admin}))
+		Expect(config.Orgs[org].Teams[team].Children["oncall"].Members).To(Equal([]string{member, "charlie"}))
+		Expect(config.Orgs[org].Teams[team].Children["oncall"].Children).To(HaveKey("primary"))
+		Expect(config.Orgs[org].Teams["app"].Children["ui"].Maintainers).To(Equal([]string{"charlie"}))
+	})
+
+	It("should create a missing child under its parent looked up by name", func() {
+		Expect(CreateTeam(config, org, "oncall/secondary", peribolos.TeamMetadata{})).To(Succeed())
+		Expect(config.Orgs[org].Teams[team].Children["oncall"].Children).To(HaveKey("secondary"))
+		Expect(CreateTeam(config, org, "ui", peribolos.TeamMetadata{})).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("Reconciling Team's maintainers", func() {
+	var (
+		err    error
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{}}
+	)
+
+	BeforeEach(func() {
+		config.Orgs = map[string]peribolos.Config{
+			org: {
+				Teams: map[string]peribolos.Team{
+					team: {
+						Members:     []string{"alice", "bob"},
+						Maintainers: []string{"alice"},
+					},
+				},
+			},
+		}
+
+		err = ReconcileTeamMaintainers(config, org, team, []string{"bob"})
+	})
+
+	It("should not error", func() {
+		Expect(err).To(Succeed())
+	})
+
+	It("should exactly match the specified maintainers", func() {
+		Expect(config.Orgs[org].Teams[team].Maintainers).To(Equal([]string{member}))
+	})
+})
+
+var _ = Describe("Diffing Team's people", func() {
+	var changes *TeamChanges
+
+	BeforeEach(func() {
+		changes = DiffTeam(org, team,
+			peribolos.Team{Members: []string{"alice", "bob"}, Maintainers: []string{"alice"}},
+			peribolos.Team{Members: []string{"alice", "charlie"}, Maintainers: []string{"alice"}},
+		)
+	})
+
+	It("should report added members", func() {
+		Expect(changes.Members.Added).To(Equal([]string{"charlie"}))
+	})
+
+	It("should report removed members", func() {
+		Expect(changes.Members.Removed).To(Equal([]string{member}))
+	})
+
+	It("should not report unchanged maintainers", func() {
+		Expect(changes.Maintainers.Empty()).To(BeTrue())
+	})
+
+	It("should not be empty", func() {
+		Expect(changes.Empty()).To(BeFalse())
+	})
+
+	It("should print the changes", func() {
+		Expect(changes.String()).To(Equal("members:\n+ charlie\n- bob\n"))
+	})
+})
+
+var _ = Describe("Mapping Team's roles", func() {
+	var (
+		err    error
+		config = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{}}
+	)
+
+	BeforeEach(func() {
+		config.Orgs = map[string]peribolos.Config{
+			org: {
+				Teams: map[string]peribolos.Team{
+					team: {
+						Members:     []string{"bob", "dave"},
+						Maintainers: []string{"alice", "eve"},
+					},
+				},
+			},
+		}
+	})
+
+	Context("adding the roles", func() {
+		BeforeEach(func() {
+			err = AddTeamRoles(config, org, team, []string{"bob", "charlie"}, []string{"alice", "bob", "frank"})
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should promote members to maintainers", func() {
+			Expect(config.Orgs[org].Teams[team].Maintainers).To(Equal([]string{"eve", "bob", "charlie"}))
+		})
+
+		It("should demote maintainers to members", func() {
+			Expect(config.Orgs[org].Teams[team].Members).To(Equal([]string{"dave", "alice", "frank"}))
+		})
+	})
+
+	Context("reconciling the roles", func() {
+		BeforeEach(func() {
+			err = ReconcileTeamRoles(config, org, team, []string{"bob", "charlie"}, []string{"alice", "bob", "frank"})
+		})
+
+		It("should not error", func() {
+			Expect(err).To(Succeed())
+		})
+
+		It("should exactly match the specified maintainers", func() {
+			Expect(config.Orgs[org].Teams[team].Maintainers).To(Equal([]string{"bob", "charlie"}))
+		})
+
+		It("should exactly match the specified members that are not maintainers", func() {
+			Expect(config.Orgs[org].Teams[team].Members).To(Equal([]string{"alice", "frank"}))
+		})
+	})
+
+	Context("the team does not exist", func() {
+		BeforeEach(func() {
+			err = AddTeamRoles(config, org, "nonexistent", []string{"charlie"}, nil)
+		})
+
+		It("should error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Enforcing Team's policy", func() {
+	var (
+		err      error
+		filtered *Filtered
+		policy   *Policy
+		config   = &peribolos.FullConfig{Orgs: map[string]peribolos.Config{}}
+	)
+
+	BeforeEach(func() {
+		config.Orgs = map[string]peribolos.Config{
+			org: {
+				Teams: map[string]peribolos.Team{
+					team: {
+						Maintainers: []string{"root-admin"},
+						Members:     []string{"alice", "ci-bot"},
+					},
+				},
+			},
+		}
+		policy = &Policy{Deny: []string{"*[bot]", "ci-*"}, Protect: []string{"ROOT-*"}}
+	})
+
+	Context("when adding people", func() {
+		BeforeEach(func() {
+			filtered, err = AddTeamMembersWithPolicy(config, org, team, []string{"bob", "dependabot[bot]", "ci-runner"},
+				policy)
+		})
+
+		It("should not add the denied handles", func() {
+			Expect(err).To(Succeed())
+			Expect(config.Orgs[org].Teams[team].Members).To(Equal([]string{"alice", "ci-bot", "bob"}))
+			Expect(filtered.Denied).To(Equal([]string{"dependabot[bot]", "ci-runner"}))
+		})
+	})
+
+	Context("when reconciling people", func() {
+		BeforeEach(func() {
+			filtered, err = ReconcileTeamRolesWithPolicy(config, org, team, []string{"alice"}, []string{"ci-bot", "robot"},
+				policy)
+		})
+
+		It("should not remove the protected handles", func() {
+			Expect(err).To(Succeed())
+			Expect(config.Orgs[org].Teams[team].Maintainers).To(Equal([]string{"alice", "root-admin"}))
+			Expect(filtered.Protected).To(Equal([]string{"root-admin"}))
+		})
+
+		It("should keep the denied handles already in the team", func() {
+			Expect(config.Orgs[org].Teams[team].Members).To(Equal([]string{"ci-bot", "robot"}))
+			Expect(filtered.Denied).To(BeEmpty())
+		})
+	})
+
+	Context("without policy", func() {
+		BeforeEach(func() {
+			filtered, err = ReconcileTeamMembersWithPolicy(config, org, team, []string{"dependabot[bot]"}, nil)
+		})
+
+		It("should not filter anything", func() {
+			Expect(err).To(Succeed())
+			Expect(config.Orgs[org].Teams[team].Members).To(Equal([]string{"dependabot[bot]"}))
+			Expect(filtered.Empty()).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,162 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peribolos
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	peribolos "k8s.io/test-infra/prow/config/org"
+)
+
+// Policy represents the handles that the updates of a Team must never add, like bots, and never remove, like
+// break-glass admins. Patterns are matched case-insensitively against the whole handle, where * matches any sequence
+// of characters and ? any single character: other characters, like the brackets of *[bot], are literal. The patterns
+// are compiled on first use, after which they must not change.
+type Policy struct {
+	// Deny represents the patterns of the handles never added to the Team.
+	Deny []string
+
+	// Protect represents the patterns of the handles never removed from the Team.
+	Protect []string
+
+	// deny and protect represent the compiled patterns, on first use.
+	deny, protect []*regexp.Regexp
+	once          sync.Once
+}
+
+// Filtered represents the handles that a Policy filtered out of the update of a Team.
+type Filtered struct {
+	// Denied represents the handles that have not been added.
+	Denied []string `json:"denied,omitempty"`
+
+	// Protected represents the handles that have not been removed.
+	Protected []string `json:"protected,omitempty"`
+}
+
+// Empty returns whether no handle has been filtered out.
+func (f *Filtered) Empty() bool {
+	return len(f.Denied) == 0 && len(f.Protected) == 0
+}
+
+// Validate validates the patterns of the Policy. It possibly returns an error.
+func (p *Policy) Validate() error {
+	for _, pattern := range append(append([]string{}, p.Deny...), p.Protect...) {
+		if strings.TrimSpace(pattern) == "" {
+			return errors.New("policy pattern is empty")
+		}
+	}
+
+	return nil
+}
+
+// Merge returns a Policy with the patterns of both policies. Any of them can be nil.
+func (p *Policy) Merge(other *Policy) *Policy {
+	merged := &Policy{}
+
+	for _, policy := range []*Policy{p, other} {
+		if policy != nil {
+			merged.Deny = append(merged.Deny, policy.Deny...)
+			merged.Protect = append(merged.Protect, policy.Protect...)
+		}
+	}
+
+	return merged
+}
+
+// enforce returns the Team after its update, without the denied handles that were not in the Team before and with
+// the protected handles that were in the Team before, in their previous role, with the filtered handles.
+func (p *Policy) enforce(before, after peribolos.Team) (peribolos.Team, *Filtered) {
+	filtered := &Filtered{}
+
+	if p == nil {
+		return after, filtered
+	}
+
+	p.once.Do(func() {
+		p.deny, p.protect = compilePatterns(p.Deny), compilePatterns(p.Protect)
+	})
+
+	inBefore := func(h string) bool {
+		return containsLogin(before.Maintainers, h) || containsLogin(before.Members, h)
+	}
+
+	inAfter := func(h string) bool {
+		return containsLogin(after.Maintainers, h) || containsLogin(after.Members, h)
+	}
+
+	deny := func(list []string) []string {
+		result := []string{}
+
+		for _, h := range list {
+			if !inBefore(h) && matchesAny(p.deny, h) {
+				if !containsLogin(filtered.Denied, h) {
+					filtered.Denied = append(filtered.Denied, h)
+				}
+
+				continue
+			}
+
+			result = append(result, h)
+		}
+
+		return result
+	}
+
+	after.Maintainers = deny(after.Maintainers)
+	after.Members = deny(after.Members)
+
+	for _, list := range []struct {
+		before []string
+		after  *[]string
+	}{
+		{before.Maintainers, &after.Maintainers},
+		{before.Members, &after.Members},
+	} {
+		for _, h := range list.before {
+			if !inAfter(h) && matchesAny(p.protect, h) {
+				*list.after = append(*list.after, h)
+				filtered.Protected = append(filtered.Protected, h)
+			}
+		}
+	}
+
+	return after, filtered
+}
+
+// compilePatterns returns the regexps of the patterns.
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		expr := "(?i)^" + strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(pattern)) + "$"
+		compiled = append(compiled, regexp.MustCompile(expr))
+	}
+
+	return compiled
+}
+
+// matchesAny returns whether the handle matches any of the patterns.
+func matchesAny(patterns []*regexp.Regexp, handle string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(handle) {
+			return true
+		}
+	}
+
+	return false
+}
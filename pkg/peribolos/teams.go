@@ -0,0 +1,176 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peribolos
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	peribolos "k8s.io/test-infra/prow/config/org"
+)
+
+// TeamSeparator separates the names of the nested Teams of a Team reference, like parent/child.
+const TeamSeparator = "/"
+
+// CreateTeam adds an empty Team with the specified metadata to the specified Organization. The Team is referenced
+// by its name or, for a child Team, by the reference of its parent, like parent/child: the parent must exist, and is
+// looked up like ResolveTeam does. It possibly returns an error.
+func CreateTeam(config *peribolos.FullConfig, org, team string, metadata peribolos.TeamMetadata) error {
+	if _, ok := config.Orgs[org]; !ok {
+		return errors.New("organization not found in peribolos config")
+	}
+
+	if _, err := ResolveTeam(config, org, team); err == nil {
+		//nolint:goerr113
+		return fmt.Errorf("team %s already exists in organization %s peribolos config", team, org)
+	}
+
+	parent, name := splitTeam(team)
+
+	if parent != "" {
+		ref, err := ResolveTeam(config, org, parent)
+		if err != nil {
+			return errors.Wrapf(err, "error looking up parent team %s", parent)
+		}
+
+		team = joinTeam(ref, name)
+	}
+
+	storeTeam(config, org, team, peribolos.Team{TeamMetadata: metadata})
+
+	return nil
+}
+
+// ResolveTeam returns the full reference of the specified Team of the Organization, like parent/child. The Team is
+// referenced by its path from a top-level Team or, when it is not a top-level Team, by its name only: then it is
+// looked up anywhere in the hierarchy, and must be unique. It possibly returns an error.
+func ResolveTeam(config *peribolos.FullConfig, org, team string) (string, error) {
+	orgConfig, ok := config.Orgs[org]
+	if !ok {
+		return "", errors.New("organization not found in peribolos config")
+	}
+
+	if _, ok = lookupTeam(orgConfig, team); ok {
+		return team, nil
+	}
+
+	found := []string{}
+
+	if !strings.Contains(team, TeamSeparator) {
+		for _, ref := range listTeams("", orgConfig.Teams) {
+			if _, name := splitTeam(ref); name == team {
+				found = append(found, ref)
+			}
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		//nolint:goerr113
+		return "", fmt.Errorf("team not found in organization %s peribolos config", org)
+	case 1:
+		return found[0], nil
+	default:
+		//nolint:goerr113
+		return "", fmt.Errorf("team %s is ambiguous in organization %s peribolos config, one of %v", team, org, found)
+	}
+}
+
+// ListTeams returns the references of every Team of the specified Organization, nested Teams included, sorted.
+// It possibly returns an error.
+func ListTeams(config *peribolos.FullConfig, org string) ([]string, error) {
+	orgConfig, ok := config.Orgs[org]
+	if !ok {
+		return nil, errors.New("organization not found in peribolos config")
+	}
+
+	return listTeams("", orgConfig.Teams), nil
+}
+
+// listTeams returns the references of the specified Teams, children of the specified parent Team if any, and of
+// their descendants, sorted.
+func listTeams(parent string, teams map[string]peribolos.Team) []string {
+	refs := []string{}
+
+	for _, name := range sortedKeys(teams) {
+		ref := joinTeam(parent, name)
+		refs = append(refs, ref)
+		refs = append(refs, listTeams(ref, teams[name].Children)...)
+	}
+
+	return refs
+}
+
+// lookupTeam returns the Team of the Organization at the specified reference, and whether it exists.
+func lookupTeam(orgConfig peribolos.Config, team string) (peribolos.Team, bool) {
+	teams := orgConfig.Teams
+	found := peribolos.Team{}
+
+	for _, name := range strings.Split(team, TeamSeparator) {
+		t, ok := teams[name]
+		if !ok {
+			return peribolos.Team{}, false
+		}
+
+		found, teams = t, t.Children
+	}
+
+	return found, true
+}
+
+// storeTeam stores the Team at the specified reference of the Organization, whose parent Team exists.
+func storeTeam(config *peribolos.FullConfig, org, team string, t peribolos.Team) {
+	parent, name := splitTeam(team)
+
+	if parent == "" {
+		orgConfig := config.Orgs[org]
+		if orgConfig.Teams == nil {
+			orgConfig.Teams = map[string]peribolos.Team{}
+		}
+
+		orgConfig.Teams[name] = t
+		config.Orgs[org] = orgConfig
+
+		return
+	}
+
+	parentTeam, _ := lookupTeam(config.Orgs[org], parent)
+	if parentTeam.Children == nil {
+		parentTeam.Children = map[string]peribolos.Team{}
+	}
+
+	parentTeam.Children[name] = t
+	storeTeam(config, org, parent, parentTeam)
+}
+
+// splitTeam returns the reference of the parent of the specified Team, empty for a top-level Team, and its name.
+func splitTeam(team string) (string, string) {
+	i := strings.LastIndex(team, TeamSeparator)
+	if i < 0 {
+		return "", team
+	}
+
+	return team[:i], team[i+1:]
+}
+
+// joinTeam returns the reference of the specified child Team of the specified parent Team, if any.
+func joinTeam(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + TeamSeparator + name
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peribolos
+
+import (
+	"fmt"
+	"strings"
+
+	peribolos "k8s.io/test-infra/prow/config/org"
+)
+
+// Rule represents a semantic rule of the Peribolos configs.
+type Rule string
+
+const (
+	// RuleInvalidLogin represents the rule that every handle is a valid GitHub login.
+	RuleInvalidLogin Rule = "invalid-login"
+
+	// RuleDuplicate represents the rule that a list of people does not contain the same handle twice.
+	RuleDuplicate Rule = "duplicate"
+
+	// RuleAdminAndMember represents the rule that an Organization admin is not listed as a member too.
+	RuleAdminAndMember Rule = "admin-and-member"
+
+	// RuleMaintainerAndMember represents the rule that a Team maintainer is not listed as a member too.
+	RuleMaintainerAndMember Rule = "maintainer-and-member"
+
+	// RuleNotOrgMember represents the rule that the people of a Team are members or admins of its Organization.
+	RuleNotOrgMember Rule = "not-org-member"
+)
+
+// Finding represents the violation of a semantic rule by a Peribolos config.
+type Finding struct {
+	Rule Rule   `json:"rule"`
+	Org  string `json:"org"`
+
+	// Team represents the path of the Team from the top-level one, like parent/child, if any.
+	Team   string `json:"team,omitempty"`
+	Handle string `json:"handle"`
+
+	Message string `json:"message"`
+}
+
+// String returns the finding in a human readable format.
+func (f *Finding) String() string {
+	if f.Team != "" {
+		return fmt.Sprintf("%s/%s: %s", f.Org, f.Team, f.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", f.Org, f.Message)
+}
+
+// Validate returns the violations of the semantic rules by the people of the Organizations and of their Teams, nested
+// ones included, that Peribolos would reject or misapply. The membership of the people of the Teams is only checked
+// for the Organizations declaring their members or admins.
+func Validate(config *peribolos.FullConfig) []Finding {
+	v := &validator{findings: []Finding{}}
+
+	for _, org := range sortedKeys(config.Orgs) {
+		orgConfig := config.Orgs[org]
+
+		v.list(org, "", "admins", orgConfig.Admins)
+		v.list(org, "", "members", orgConfig.Members)
+		v.overlap(org, "", RuleAdminAndMember, "an admin", "a member", orgConfig.Admins, orgConfig.Members)
+
+		v.teams(org, "", orgConfig.Teams, &orgConfig)
+	}
+
+	return v.findings
+}
+
+type validator struct {
+	findings []Finding
+}
+
+func (v *validator) add(rule Rule, org, team, handle, format string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{
+		Rule:    rule,
+		Org:     org,
+		Team:    team,
+		Handle:  handle,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// teams validates the people of the specified Teams and of their children.
+func (v *validator) teams(org, parent string, teams map[string]peribolos.Team, orgConfig *peribolos.Config) {
+	managed := managesMembership(orgConfig)
+
+	for _, name := range sortedKeys(teams) {
+		team, path := teams[name], joinTeam(parent, name)
+
+		v.list(org, path, "maintainers", team.Maintainers)
+		v.list(org, path, "members", team.Members)
+		v.overlap(org, path, RuleMaintainerAndMember, "a maintainer", "a member", team.Maintainers, team.Members)
+
+		if managed {
+			people := with(team.Maintainers, team.Members)
+
+			for _, handle := range without(without(people, orgConfig.Members), orgConfig.Admins) {
+				v.add(RuleNotOrgMember, org, path, handle, "%s is not a member of organization %s", handle, org)
+			}
+		}
+
+		v.teams(org, path, team.Children, orgConfig)
+	}
+}
+
+// list validates the handles of a list of people.
+func (v *validator) list(org, team, name string, handles []string) {
+	seen := []string{}
+
+	for _, handle := range handles {
+		if !IsValidLogin(handle) {
+			v.add(RuleInvalidLogin, org, team, handle, "%s in %s is not a valid GitHub login", handle, name)
+		}
+
+		if containsLogin(seen, handle) {
+			v.add(RuleDuplicate, org, team, handle, "%s is listed more than once in %s", handle, name)
+
+			continue
+		}
+
+		seen = append(seen, handle)
+	}
+}
+
+// overlap validates that no handle is in both lists of people.
+func (v *validator) overlap(org, team string, rule Rule, first, second string, a, b []string) {
+	for _, handle := range a {
+		if containsLogin(b, handle) {
+			v.add(rule, org, team, handle, "%s is both %s and %s", handle, first, second)
+		}
+	}
+}
+
+// NewFindings returns the findings that are not in the previous ones.
+func NewFindings(previous, current []Finding) []Finding {
+	result := []Finding{}
+
+	for _, f := range current {
+		found := false
+
+		for _, p := range previous {
+			if p.Rule == f.Rule && p.Org == f.Org && p.Team == f.Team && strings.EqualFold(p.Handle, f.Handle) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
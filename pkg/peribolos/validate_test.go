@@ -0,0 +1,121 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package peribolos_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	peribolos "k8s.io/test-infra/prow/config/org"
+	"sigs.k8s.io/yaml"
+
+	. "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+var _ = Describe("Validating Peribolos config", func() {
+	var config *peribolos.FullConfig
+
+	BeforeEach(func() {
+		config = NewConfig()
+		Expect(yaml.Unmarshal([]byte(`orgs:
+  acme:
+    admins: [alice]
+    members: [bob, charlie]
+    teams:
+      app:
+        maintainers: [alice]
+        members: [bob]
+        teams:
+          ui:
+            members: [charlie]
+`), config)).To(Succeed())
+	})
+
+	It("should not find anything in a valid config", func() {
+		Expect(Validate(config)).To(BeEmpty())
+	})
+
+	It("should find the people in both roles", func() {
+		config.Orgs[org].Teams["app"] = peribolos.Team{Maintainers: []string{"alice"}, Members: []string{"Alice"}}
+
+		Expect(Validate(config)).To(Equal([]Finding{{
+			Rule:    RuleMaintainerAndMember,
+			Org:     org,
+			Team:    "app",
+			Handle:  "alice",
+			Message: "alice is both a maintainer and a member",
+		}}))
+	})
+
+	It("should find the admins listed as members", func() {
+		orgConfig := config.Orgs[org]
+		orgConfig.Members = append(orgConfig.Members, admin)
+		config.Orgs[org] = orgConfig
+
+		Expect(Validate(config)).To(ConsistOf(HaveField("Rule", RuleAdminAndMember)))
+	})
+
+	It("should find the duplicates and the invalid logins", func() {
+		orgConfig := config.Orgs[org]
+		orgConfig.Members = append(orgConfig.Members, "BOB", "dave-")
+		config.Orgs[org] = orgConfig
+
+		Expect(Validate(config)).To(Equal([]Finding{
+			{Rule: RuleDuplicate, Org: org, Handle: "BOB", Message: "BOB is listed more than once in members"},
+			{Rule: RuleInvalidLogin, Org: org, Handle: "dave-", Message: "dave- in members is not a valid GitHub login"},
+		}))
+	})
+
+	It("should find the people of nested teams that are not org members", func() {
+		child := config.Orgs[org].Teams["app"].Children["ui"]
+		child.Members = append(child.Members, "erin")
+		config.Orgs[org].Teams["app"].Children["ui"] = child
+
+		Expect(Validate(config)).To(Equal([]Finding{{
+			Rule:    RuleNotOrgMember,
+			Org:     org,
+			Team:    "app/ui",
+			Handle:  "erin",
+			Message: "erin is not a member of organization acme",
+		}}))
+	})
+
+	It("should not check the membership when the org does not declare its people", func() {
+		orgConfig := config.Orgs[org]
+		orgConfig.Admins, orgConfig.Members = nil, nil
+		config.Orgs[org] = orgConfig
+
+		Expect(Validate(config)).To(BeEmpty())
+	})
+
+	It("should only return the new findings", func() {
+		previous := []Finding{{Rule: RuleDuplicate, Org: org, Handle: "bob"}}
+		current := []Finding{{Rule: RuleDuplicate, Org: org, Handle: "Bob"}, {Rule: RuleDuplicate, Org: org, Handle: "dave"}}
+
+		Expect(NewFindings(previous, current)).To(Equal(current[1:]))
+	})
+
+	DescribeTable("should validate the logins length",
+		func(login string, valid bool) {
+			Expect(IsValidLogin(login)).To(Equal(valid))
+		},
+		Entry("39 characters", strings.Repeat("a", 39), true),
+		Entry("40 characters", strings.Repeat("a", 40), false),
+		Entry("39 characters with hyphens", "a"+strings.Repeat("-b", 19), true),
+		Entry("41 characters with hyphens", "a"+strings.Repeat("-b", 20), false),
+		Entry("77 characters with hyphens", "a"+strings.Repeat("-b", 38), false),
+	)
+})
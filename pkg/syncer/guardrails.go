@@ -0,0 +1,198 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+const percent = 100
+
+// Limit represents a maximum number of handles, either absolute or as a percentage of the Team size.
+// The zero value is unlimited.
+type Limit struct {
+	// Value represents the maximum number, or percentage, of handles.
+	Value int
+
+	// Percent represents the option to consider the value as a percentage of the Team size before the changes.
+	Percent bool
+}
+
+// ParseLimit parses a limit, either a number like 10 or a percentage like 20%. An empty string is unlimited.
+// It possibly returns an error.
+func ParseLimit(s string) (Limit, error) {
+	if s == "" {
+		return Limit{}, nil
+	}
+
+	l := Limit{Percent: strings.HasSuffix(s, "%")}
+
+	v, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+	if err != nil || v <= 0 {
+		//nolint:goerr113
+		return Limit{}, fmt.Errorf("invalid limit %q, must be a positive number or percentage", s)
+	}
+
+	l.Value = v
+
+	return l, nil
+}
+
+// String returns the limit as it is parsed.
+func (l Limit) String() string {
+	if l.Percent {
+		return fmt.Sprintf("%d%%", l.Value)
+	}
+
+	return strconv.Itoa(l.Value)
+}
+
+// exceeded returns whether the specified number of handles exceeds the limit, for a Team of the specified size.
+// Percentages do not apply to empty Teams.
+func (l Limit) exceeded(n, size int) bool {
+	switch {
+	case l.Value == 0:
+		return false
+	case l.Percent:
+		return size > 0 && n*percent > l.Value*size
+	default:
+		return n > l.Value
+	}
+}
+
+// Guardrails represents the limits of the changes a sync applies to every Team, protecting them from being wiped or
+// flooded by a broken source of truth.
+type Guardrails struct {
+	// MaxAdded represents the maximum number of handles added to a Team.
+	MaxAdded Limit
+
+	// MaxRemoved represents the maximum number of handles removed from a Team.
+	MaxRemoved Limit
+
+	// MinTeamSize represents the size under which the people of a Team are not removed anymore. Zero is no minimum.
+	MinTeamSize int
+
+	// Override represents the option to apply the changes anyway, only reporting the violations.
+	Override bool
+}
+
+// Violation represents a change to a Team beyond the guardrails.
+type Violation struct {
+	Team   string `json:"team"`
+	Reason string `json:"reason"`
+}
+
+func (v *Violation) String() string {
+	return fmt.Sprintf("team %s: %s", v.Team, v.Reason)
+}
+
+// Check returns the violations of the guardrails by the changes of a Team, whose people after the changes are the
+// specified ones. Leaving a Team that had maintainers without any is always a violation.
+func (g *Guardrails) Check(changes *orgs.TeamChanges, maintainers, members int) []Violation {
+	added := len(changes.Maintainers.Added) + len(changes.Members.Added)
+	removed := len(changes.Maintainers.Removed) + len(changes.Members.Removed)
+
+	// The people moved between the roles are neither added nor removed.
+	moved := len(intersection(changes.Maintainers.Added, changes.Members.Removed)) +
+		len(intersection(changes.Members.Added, changes.Maintainers.Removed))
+	added, removed = added-moved, removed-moved
+
+	after := maintainers + members
+	before := after - added + removed
+	maintainersBefore := maintainers - len(changes.Maintainers.Added) + len(changes.Maintainers.Removed)
+
+	violations := []Violation{}
+	violate := func(format string, args ...interface{}) {
+		violations = append(violations, Violation{Team: changes.Team, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	if g.MaxAdded.exceeded(added, before) {
+		violate("adds %d of %d handles, more than the maximum of %s", added, before, g.MaxAdded)
+	}
+
+	if g.MaxRemoved.exceeded(removed, before) {
+		violate("removes %d of %d handles, more than the maximum of %s", removed, before, g.MaxRemoved)
+	}
+
+	if removed > 0 && after < g.MinTeamSize {
+		violate("leaves %d handles, less than the minimum of %d", after, g.MinTeamSize)
+	}
+
+	if maintainersBefore > 0 && maintainers == 0 {
+		violate("removes every maintainer")
+	}
+
+	return violations
+}
+
+// checkGuardrails returns the violations of the guardrails, if any, by the changes applied to a Team of the Peribolos
+// config. It possibly returns an error.
+func (s *Syncer) checkGuardrails(config *peribolos.FullConfig, changes *orgs.TeamChanges) ([]Violation, error) {
+	if s.Guardrails == nil {
+		return []Violation{}, nil
+	}
+
+	team, err := orgs.GetTeam(config, changes.Org, changes.Team)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Guardrails.Check(changes, len(team.Maintainers), len(team.Members)), nil
+}
+
+// GuardrailsError represents the refusal to apply changes beyond the guardrails.
+type GuardrailsError struct {
+	Violations []Violation
+}
+
+func (e *GuardrailsError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("changes beyond the guardrails, refusing to apply them:")
+
+	for i := range e.Violations {
+		fmt.Fprintf(&b, "\n- %s", &e.Violations[i])
+	}
+
+	return b.String()
+}
+
+// IsGuardrailsError returns whether the error is a refusal to apply changes beyond the guardrails.
+func IsGuardrailsError(err error) bool {
+	var e *GuardrailsError
+
+	return errors.As(err, &e)
+}
+
+func intersection(a, b []string) []string {
+	result := []string{}
+
+	for _, v := range a {
+		for _, w := range b {
+			if strings.EqualFold(v, w) {
+				result = append(result, v)
+			}
+		}
+	}
+
+	return result
+}
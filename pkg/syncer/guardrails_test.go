@@ -0,0 +1,115 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+var _ = Describe("Parsing guardrail limits", func() {
+	It("should parse numbers and percentages", func() {
+		Expect(syncer.ParseLimit("")).To(Equal(syncer.Limit{}))
+		Expect(syncer.ParseLimit("10")).To(Equal(syncer.Limit{Value: 10}))
+		Expect(syncer.ParseLimit("20%")).To(Equal(syncer.Limit{Value: 20, Percent: true}))
+	})
+
+	It("should fail on malformed limits", func() {
+		for _, s := range []string{"ten", "0", "-1", "%", "10%%"} {
+			_, err := syncer.ParseLimit(s)
+			Expect(err).ToNot(Succeed(), s)
+		}
+	})
+})
+
+var _ = Describe("Checking guardrails", func() {
+	var (
+		guardrails *syncer.Guardrails
+		changes    *orgs.TeamChanges
+	)
+
+	BeforeEach(func() {
+		guardrails = &syncer.Guardrails{}
+		changes = &orgs.TeamChanges{
+			Team:    "app",
+			Members: orgs.Changes{Added: []string{"dave", "erin"}, Removed: []string{"bob"}},
+		}
+	})
+
+	It("should not limit the changes by default", func() {
+		Expect(guardrails.Check(changes, 1, 3)).To(BeEmpty())
+	})
+
+	It("should limit the number of handles added and removed", func() {
+		guardrails.MaxAdded = syncer.Limit{Value: 1}
+		guardrails.MaxRemoved = syncer.Limit{Value: 1}
+
+		Expect(guardrails.Check(changes, 1, 3)).To(Equal([]syncer.Violation{{
+			Team:   "app",
+			Reason: "adds 2 of 3 handles, more than the maximum of 1",
+		}}))
+	})
+
+	It("should limit the percentage of handles added and removed", func() {
+		guardrails.MaxRemoved = syncer.Limit{Value: 30, Percent: true}
+
+		Expect(guardrails.Check(changes, 1, 3)).To(Equal([]syncer.Violation{{
+			Team:   "app",
+			Reason: "removes 1 of 3 handles, more than the maximum of 30%",
+		}}))
+	})
+
+	It("should not apply percentages to empty teams", func() {
+		guardrails.MaxAdded = syncer.Limit{Value: 10, Percent: true}
+		changes.Members.Removed = nil
+
+		Expect(guardrails.Check(changes, 0, 2)).To(BeEmpty())
+	})
+
+	It("should not count the people moved between roles", func() {
+		guardrails.MaxAdded = syncer.Limit{Value: 1}
+		changes = &orgs.TeamChanges{
+			Team:        "app",
+			Maintainers: orgs.Changes{Added: []string{"bob"}},
+			Members:     orgs.Changes{Added: []string{"dave"}, Removed: []string{"bob"}},
+		}
+
+		Expect(guardrails.Check(changes, 2, 1)).To(BeEmpty())
+	})
+
+	It("should refuse to remove people under the minimum team size", func() {
+		guardrails.MinTeamSize = 5
+
+		Expect(guardrails.Check(changes, 1, 3)).To(Equal([]syncer.Violation{{
+			Team:   "app",
+			Reason: "leaves 4 handles, less than the minimum of 5",
+		}}))
+
+		changes.Members.Removed = nil
+		Expect(guardrails.Check(changes, 1, 4)).To(BeEmpty())
+	})
+
+	It("should refuse to remove every maintainer", func() {
+		changes.Maintainers.Removed = []string{"alice"}
+
+		Expect(guardrails.Check(changes, 0, 3)).To(Equal([]syncer.Violation{{
+			Team:   "app",
+			Reason: "removes every maintainer",
+		}}))
+	})
+})
@@ -0,0 +1,52 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// HandleMapping represents the mapping of the GitHub handles used by the sources of truth, like renamed accounts or
+// corporate aliases, to the current GitHub logins. Keys are lowercase, as GitHub logins are case-insensitive.
+type HandleMapping map[string]string
+
+// Map returns the GitHub login of the specified handle, or the handle itself when not mapped.
+func (m HandleMapping) Map(handle string) string {
+	if login, ok := m[strings.ToLower(handle)]; ok {
+		return login
+	}
+
+	return handle
+}
+
+// Apply returns the people with their handles mapped, sorted and without duplicates.
+func (m HandleMapping) Apply(people *People) *People {
+	if len(m) == 0 {
+		return people
+	}
+
+	mapped := func(handles []string) []string {
+		result := sets.NewString()
+		for _, h := range handles {
+			result.Insert(m.Map(h))
+		}
+
+		return result.List()
+	}
+
+	return &People{Approvers: mapped(people.Approvers), Reviewers: mapped(people.Reviewers)}
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"fmt"
+	"strings"
+
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+// OrgMembership represents how the people added to a Team that are not members of its Organization are handled, as
+// Peribolos rejects them.
+type OrgMembership string
+
+const (
+	// OrgMembershipIgnore represents leaving the members of the Organization as they are.
+	OrgMembershipIgnore OrgMembership = "ignore"
+
+	// OrgMembershipAdd represents adding the missing people to the members of the Organization.
+	OrgMembershipAdd OrgMembership = "add"
+
+	// OrgMembershipRequire represents failing the sync when people are missing from the Organization.
+	OrgMembershipRequire OrgMembership = "require"
+)
+
+// OrgMemberships represents the supported OrgMembership values.
+var OrgMemberships = []OrgMembership{OrgMembershipIgnore, OrgMembershipAdd, OrgMembershipRequire}
+
+// ValidateOrgMembership validates the specified OrgMembership. It possibly returns an error.
+func ValidateOrgMembership(m OrgMembership) error {
+	for _, v := range OrgMemberships {
+		if v == m {
+			return nil
+		}
+	}
+
+	//nolint:goerr113
+	return fmt.Errorf("unknown org membership %q, must be one of %v", m, OrgMemberships)
+}
+
+// MissingOrgMembers represents the people added to a Team that are not members of its Organization.
+type MissingOrgMembers struct {
+	Org     string
+	Team    string
+	Handles []string
+}
+
+// OrgMembershipError represents the people added to the Teams that are not members of their Organization.
+type OrgMembershipError struct {
+	Missing []MissingOrgMembers
+}
+
+func (e *OrgMembershipError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("people added to the teams are not organization members:")
+
+	for _, m := range e.Missing {
+		fmt.Fprintf(&b, "\n- team %s: %s", m.Team, strings.Join(m.Handles, ", "))
+	}
+
+	return b.String()
+}
+
+// applyOrgMembership handles the people added by the Team changes that are not members of the Organization, adding
+// them to its members or returning them as missing. It possibly returns an error.
+func applyOrgMembership(config *peribolos.FullConfig, changes *orgs.TeamChanges, m OrgMembership) ([]string, error) {
+	added := append(append([]string{}, changes.Maintainers.Added...), changes.Members.Added...)
+
+	switch m {
+	case OrgMembershipAdd:
+		members, err := orgs.AddOrgMembers(config, changes.Org, added)
+		if err != nil {
+			return nil, err
+		}
+
+		changes.OrgMembers = members
+
+		return []string{}, nil
+	case OrgMembershipRequire:
+		return orgs.MissingOrgMembers(config, changes.Org, added)
+	default:
+		return []string{}, nil
+	}
+}
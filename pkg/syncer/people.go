@@ -0,0 +1,45 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+// People represents the people loaded from a source of truth, by role.
+type People struct {
+	// Approvers represents the GitHub handles of the approvers.
+	Approvers []string
+
+	// Reviewers represents the GitHub handles of the reviewers.
+	Reviewers []string
+}
+
+// All returns both the approvers and the reviewers, sorted and without duplicates.
+func (p *People) All() []string {
+	return sets.NewString(p.Approvers...).Insert(p.Reviewers...).List()
+}
+
+// ReviewersOnly returns the reviewers that are not approvers, sorted and without duplicates.
+func (p *People) ReviewersOnly() []string {
+	return sets.NewString(p.Reviewers...).Difference(sets.NewString(p.Approvers...)).List()
+}
+
+// IsValidLogin returns whether the specified string is a valid GitHub login.
+func IsValidLogin(login string) bool {
+	return orgs.IsValidLogin(login)
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const fileStoreMode = 0o644
+
+// FileStore represents a Peribolos config stored on the local filesystem.
+type FileStore struct {
+	// Path represents the path to the Peribolos config file.
+	Path string
+}
+
+// Load returns the content of the Peribolos config file. It possibly returns an error.
+func (s *FileStore) Load() ([]byte, error) {
+	b, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading peribolos config file")
+	}
+
+	return b, nil
+}
+
+// Save writes the content of the Peribolos config file. It possibly returns an error.
+func (s *FileStore) Save(b []byte) error {
+	if err := os.WriteFile(s.Path, b, fileStoreMode); err != nil {
+		return errors.Wrap(err, "error writing peribolos config file")
+	}
+
+	return nil
+}
@@ -0,0 +1,431 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package syncer provides the engine that synchronizes GitHub Teams in a Peribolos config with their source of truth.
+// The source of truth, the storage of the config and the publication of the changes are pluggable.
+package syncer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+)
+
+// PeopleSource represents a source of truth of the people of a GitHub Team.
+type PeopleSource interface {
+	// LoadPeople returns the people of the source of truth. The Peribolos config is the one being synchronized, before
+	// any change, for the sources referring to its Teams, or after the changes of the other bindings for the derived
+	// bindings. It possibly returns an error.
+	LoadPeople(config *peribolos.FullConfig) (*People, error)
+}
+
+// ConfigStore represents the storage of a Peribolos config.
+type ConfigStore interface {
+	// Load returns the content of the Peribolos config. It possibly returns an error.
+	Load() ([]byte, error)
+
+	// Save stores the updated content of the Peribolos config. It possibly returns an error.
+	Save(b []byte) error
+}
+
+// Publisher represents the publication of the changes saved to a Peribolos config, for example via a Pull Request.
+type Publisher interface {
+	// Publish publishes the changes of the plan. It possibly returns an error.
+	Publish(plan *Plan) error
+}
+
+// Binding represents a binding between a GitHub Team and its source of truth.
+type Binding struct {
+	// Org represents the name of the GitHub organization of the Team.
+	Org string
+
+	// Team represents the name of the GitHub Team.
+	Team string
+
+	// Source represents the source of truth of the Team people.
+	Source PeopleSource
+
+	// MapRoles represents the option to map approvers to Team maintainers and reviewers to Team members.
+	MapRoles bool
+
+	// Optional represents the option to skip the binding when its Team is not in the Peribolos config, instead of
+	// failing.
+	Optional bool
+
+	// Policy represents the handles never added to and never removed from the Team. It is optional.
+	Policy *orgs.Policy
+
+	// Create represents the metadata of the Team to add to the Peribolos config when it is not there yet, instead of
+	// failing or skipping the binding. A child Team is only added under an existing parent. It is optional.
+	Create *peribolos.TeamMetadata
+
+	// Derived represents the option to load the people after the changes of the non-derived bindings, and of the
+	// previous derived ones, for the sources derived from other Teams of the Peribolos config. This way, the changes
+	// to those Teams flow into the derived Team in the same sync.
+	Derived bool
+}
+
+// Syncer synchronizes GitHub Teams in a Peribolos config with their source of truth.
+type Syncer struct {
+	// Bindings represents the GitHub Teams to synchronize, with their source of truth.
+	Bindings []Binding
+
+	// Store represents the storage of the Peribolos config.
+	Store ConfigStore
+
+	// Publisher represents the publication of the changes, if any. It is optional.
+	Publisher Publisher
+
+	// Reconcile represents the option to remove from the Teams the people not in the source of truth anymore.
+	Reconcile bool
+
+	// Handles represents the mapping of the handles of the sources of truth to GitHub logins. It is optional.
+	Handles HandleMapping
+
+	// Guardrails represents the limits of the changes to every Team, beyond which the sync is refused. It is optional.
+	Guardrails *Guardrails
+
+	// OrgMembership represents how the people added to the Teams that are not members of their Organization are
+	// handled. It defaults to OrgMembershipIgnore.
+	OrgMembership OrgMembership
+
+	// Validate represents the option to refuse the changes that make the Peribolos config semantically invalid. The
+	// people that are not members of their Organization are not refused when ignoring the Organization membership.
+	Validate bool
+}
+
+// Plan represents the changes a sync applies to the Peribolos config.
+type Plan struct {
+	// Changes represents the changes to every synchronized Team, in the same order of the bindings, including the
+	// empty ones. The skipped optional bindings have no changes.
+	Changes []*orgs.TeamChanges
+
+	// Before represents the content of the Peribolos config before the changes.
+	Before []byte
+
+	// After represents the content of the Peribolos config after the changes.
+	After []byte
+
+	// Skipped represents the Teams of the optional bindings that are not in the Peribolos config.
+	Skipped []string
+
+	// Violations represents the changes beyond the guardrails, if any.
+	Violations []Violation
+
+	// Findings represents the semantic problems of the Peribolos config introduced by the changes, when validated.
+	Findings []orgs.Finding
+}
+
+// Drift returns the non-empty Team changes of the Plan.
+func (p *Plan) Drift() []*orgs.TeamChanges {
+	changes := []*orgs.TeamChanges{}
+
+	for _, c := range p.Changes {
+		if !c.Empty() {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
+// InSync returns whether the synchronized Teams already match their source of truth.
+func (p *Plan) InSync() bool {
+	return len(p.Drift()) == 0
+}
+
+// Plan loads the people of every binding and the Peribolos config, and returns the changes a sync would apply,
+// without saving nor publishing anything. It possibly returns an error.
+func (s *Syncer) Plan() (*Plan, error) {
+	src, err := s.Store.Load()
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading the peribolos config")
+	}
+
+	config, err := orgs.LoadConfig(src)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.createTeams(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load every source of truth before any change to the config, except the derived ones.
+	people := make([]*People, len(s.Bindings))
+	kept := make([]bool, len(s.Bindings))
+	skipped := []string{}
+
+	for i, b := range s.Bindings {
+		if _, err := orgs.GetTeam(config, b.Org, b.Team); err != nil && b.Optional {
+			skipped = append(skipped, b.Team)
+
+			continue
+		}
+
+		kept[i] = true
+
+		if b.Derived {
+			continue
+		}
+
+		if people[i], err = b.Source.LoadPeople(config); err != nil {
+			return nil, errors.Wrapf(err, "error loading people of github team %s", b.Team)
+		}
+	}
+
+	changes := make([]*orgs.TeamChanges, len(s.Bindings))
+	violations := []Violation{}
+	missing := []MissingOrgMembers{}
+
+	update := func(i int) error {
+		b := s.Bindings[i]
+
+		teamChanges, err := UpdateTeam(config, b.Org, b.Team, s.Handles.Apply(people[i]), s.Reconcile, b.MapRoles,
+			b.Policy)
+		if err != nil {
+			return errors.Wrapf(err, "error updating github team %s", b.Team)
+		}
+
+		changes[i] = teamChanges
+		teamChanges.Created = created[i]
+
+		notMembers, err := applyOrgMembership(config, teamChanges, s.OrgMembership)
+		if err != nil {
+			return errors.Wrapf(err, "error updating the members of github organization %s", b.Org)
+		}
+
+		if len(notMembers) > 0 {
+			missing = append(missing, MissingOrgMembers{Org: b.Org, Team: b.Team, Handles: notMembers})
+		}
+
+		teamViolations, err := s.checkGuardrails(config, teamChanges)
+		if err != nil {
+			return err
+		}
+
+		violations = append(violations, teamViolations...)
+
+		return nil
+	}
+
+	for i, b := range s.Bindings {
+		if kept[i] && !b.Derived {
+			if err = update(i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The derived sources see the changes applied so far.
+	for i, b := range s.Bindings {
+		if !kept[i] || !b.Derived {
+			continue
+		}
+
+		if people[i], err = b.Source.LoadPeople(config); err != nil {
+			return nil, errors.Wrapf(err, "error loading people of github team %s", b.Team)
+		}
+
+		if err = update(i); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, &OrgMembershipError{Missing: missing}
+	}
+
+	patched, err := orgs.PatchConfig(src, config)
+	if err != nil {
+		return nil, errors.Wrap(err, "error recompiling the peribolos config")
+	}
+
+	findings, err := s.validate(src, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{
+		Changes:    compact(changes),
+		Before:     src,
+		After:      patched,
+		Skipped:    skipped,
+		Violations: violations,
+		Findings:   findings,
+	}, nil
+}
+
+// createTeams adds the missing Teams of the bindings to create to the Peribolos config. It returns whether the Team of
+// each binding has been created. It possibly returns an error.
+func (s *Syncer) createTeams(config *peribolos.FullConfig) ([]bool, error) {
+	created := make([]bool, len(s.Bindings))
+
+	for i, b := range s.Bindings {
+		if b.Create == nil {
+			continue
+		}
+
+		if _, err := orgs.GetTeam(config, b.Org, b.Team); err == nil {
+			continue
+		}
+
+		if err := orgs.CreateTeam(config, b.Org, b.Team, *b.Create); err != nil {
+			return nil, errors.Wrapf(err, "error creating github team %s", b.Team)
+		}
+
+		created[i] = true
+	}
+
+	return created, nil
+}
+
+// validate returns the semantic problems of the updated Peribolos config that are not in the original one, when
+// validating. The people that are not members of their Organization are left out when ignoring the Organization
+// membership, as it is up to the user then. It possibly returns an error.
+func (s *Syncer) validate(src []byte, config *peribolos.FullConfig) ([]orgs.Finding, error) {
+	if !s.Validate {
+		return []orgs.Finding{}, nil
+	}
+
+	original, err := orgs.LoadConfig(src)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := orgs.NewFindings(orgs.Validate(original), orgs.Validate(config))
+	if s.OrgMembership == OrgMembershipAdd || s.OrgMembership == OrgMembershipRequire {
+		return findings, nil
+	}
+
+	result := []orgs.Finding{}
+
+	for _, f := range findings {
+		if f.Rule != orgs.RuleNotOrgMember {
+			result = append(result, f)
+		}
+	}
+
+	return result, nil
+}
+
+// ValidationError represents the refusal to apply changes that make the Peribolos config invalid.
+type ValidationError struct {
+	Findings []orgs.Finding
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("changes make the peribolos config invalid, refusing to apply them:")
+
+	for i := range e.Findings {
+		fmt.Fprintf(&b, "\n- %s", &e.Findings[i])
+	}
+
+	return b.String()
+}
+
+// compact returns the changes of the bindings that have not been skipped.
+func compact(changes []*orgs.TeamChanges) []*orgs.TeamChanges {
+	compacted := make([]*orgs.TeamChanges, 0, len(changes))
+
+	for _, c := range changes {
+		if c != nil {
+			compacted = append(compacted, c)
+		}
+	}
+
+	return compacted
+}
+
+// Sync plans the changes, then saves and publishes them. Nothing is saved nor published when the Teams are already
+// in sync, nor when the changes are beyond the guardrails and they are not overridden, in which case a
+// GuardrailsError is returned, nor when they make the config invalid, in which case a ValidationError is returned.
+// It returns the applied plan. It possibly returns an error.
+func (s *Syncer) Sync() (*Plan, error) {
+	plan, err := s.Plan()
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.InSync() {
+		return plan, nil
+	}
+
+	if len(plan.Findings) > 0 {
+		return nil, &ValidationError{Findings: plan.Findings}
+	}
+
+	if len(plan.Violations) > 0 && !s.Guardrails.Override {
+		return nil, &GuardrailsError{Violations: plan.Violations}
+	}
+
+	if err = s.Store.Save(plan.After); err != nil {
+		return nil, errors.Wrap(err, "error saving the peribolos config")
+	}
+
+	if s.Publisher != nil {
+		if err = s.Publisher.Publish(plan); err != nil {
+			return nil, errors.Wrap(err, "error publishing the changes")
+		}
+	}
+
+	return plan, nil
+}
+
+// UpdateTeam updates the people of the specified Team in the Peribolos config with the specified people. When
+// reconcile is true, the people not specified are removed from the Team. When mapRoles is true, the approvers become
+// Team maintainers and the other reviewers Team members. The policy, if any, filters the handles added and removed.
+// It returns the changes applied to the Team, with the filtered handles. It possibly returns an error.
+func UpdateTeam(config *peribolos.FullConfig, org, team string, people *People,
+	reconcile, mapRoles bool, policy *orgs.Policy,
+) (*orgs.TeamChanges, error) {
+	before, err := orgs.GetTeam(config, org, team)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered *orgs.Filtered
+
+	switch {
+	case mapRoles && reconcile:
+		filtered, err = orgs.ReconcileTeamRolesWithPolicy(config, org, team, people.Approvers, people.ReviewersOnly(), policy)
+	case mapRoles:
+		filtered, err = orgs.AddTeamRolesWithPolicy(config, org, team, people.Approvers, people.ReviewersOnly(), policy)
+	case reconcile:
+		filtered, err = orgs.ReconcileTeamMembersWithPolicy(config, org, team, people.All(), policy)
+	default:
+		filtered, err = orgs.AddTeamMembersWithPolicy(config, org, team, people.All(), policy)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "error updating team members")
+	}
+
+	after, err := orgs.GetTeam(config, org, team)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := orgs.DiffTeam(org, team, before, after)
+	changes.Filtered = *filtered
+
+	return changes, nil
+}
@@ -0,0 +1,448 @@
+// Copyright 2023 The Falco Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syncer_test
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	peribolos "k8s.io/test-infra/prow/config/org"
+
+	orgs "github.com/falcosecurity/peribolos-syncer/pkg/peribolos"
+	"github.com/falcosecurity/peribolos-syncer/pkg/syncer"
+)
+
+type fakeSource struct {
+	people *syncer.People
+	err    error
+}
+
+func (s *fakeSource) LoadPeople(_ *peribolos.FullConfig) (*syncer.People, error) {
+	return s.people, s.err
+}
+
+// teamSource returns the members of a team of the config.
+type teamSource struct {
+	team string
+}
+
+func (s *teamSource) LoadPeople(config *peribolos.FullConfig) (*syncer.People, error) {
+	team, err := orgs.GetTeam(config, "acme", s.team)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syncer.People{Reviewers: team.Members}, nil
+}
+
+type fakeStore struct {
+	config []byte
+	saved  []byte
+}
+
+func (s *fakeStore) Load() ([]byte, error) {
+	return s.config, nil
+}
+
+func (s *fakeStore) Save(b []byte) error {
+	s.saved = b
+
+	return nil
+}
+
+type fakePublisher struct {
+	published *syncer.Plan
+}
+
+func (p *fakePublisher) Publish(plan *syncer.Plan) error {
+	p.published = plan
+
+	return nil
+}
+
+const config = `orgs:
+  acme:
+    teams:
+      app:
+        maintainers:
+        - alice
+        members:
+        - bob
+`
+
+var _ = Describe("Synchronizing teams", func() {
+	var (
+		err       error
+		plan      *syncer.Plan
+		source    *fakeSource
+		store     *fakeStore
+		publisher *fakePublisher
+		s         *syncer.Syncer
+	)
+
+	BeforeEach(func() {
+		source = &fakeSource{people: &syncer.People{Approvers: []string{"alice"}, Reviewers: []string{"charlie"}}}
+		store = &fakeStore{config: []byte(config)}
+		publisher = &fakePublisher{}
+		s = &syncer.Syncer{
+			Bindings:  []syncer.Binding{{Org: "acme", Team: "app", Source: source, MapRoles: true}},
+			Store:     store,
+			Publisher: publisher,
+		}
+	})
+
+	Context("when planning", func() {
+		JustBeforeEach(func() {
+			plan, err = s.Plan()
+		})
+
+		It("should return the changes", func() {
+			Expect(err).To(Succeed())
+			Expect(plan.Drift()).To(Equal([]*orgs.TeamChanges{{
+				Org:     "acme",
+				Team:    "app",
+				Members: orgs.Changes{Added: []string{"charlie"}},
+			}}))
+			Expect(string(plan.Before)).To(Equal(config))
+			Expect(string(plan.After)).To(ContainSubstring("- charlie"))
+		})
+
+		It("should neither save nor publish", func() {
+			Expect(err).To(Succeed())
+			Expect(store.saved).To(BeNil())
+			Expect(publisher.published).To(BeNil())
+		})
+
+		Context("with reconciliation", func() {
+			BeforeEach(func() {
+				s.Reconcile = true
+			})
+
+			It("should also remove the people not in the source of truth", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.Drift()[0].Members).To(Equal(orgs.Changes{
+					Added:   []string{"charlie"},
+					Removed: []string{"bob"},
+				}))
+			})
+		})
+
+		Context("when the source of truth fails", func() {
+			BeforeEach(func() {
+				source.err = errors.New("unavailable")
+			})
+
+			It("should fail", func() {
+				Expect(err).To(MatchError(ContainSubstring("unavailable")))
+			})
+		})
+
+		Context("when the team does not exist", func() {
+			BeforeEach(func() {
+				s.Bindings[0].Team = "unknown"
+			})
+
+			It("should fail", func() {
+				Expect(err).ToNot(Succeed())
+			})
+		})
+
+		Context("when the team to create does not exist", func() {
+			BeforeEach(func() {
+				description := "The new team."
+				s.Bindings[0].Team = "new"
+				s.Bindings[0].Create = &peribolos.TeamMetadata{Description: &description}
+			})
+
+			It("should create it with the people of the source of truth", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.Drift()).To(Equal([]*orgs.TeamChanges{{
+					Org:         "acme",
+					Team:        "new",
+					Created:     true,
+					Maintainers: orgs.Changes{Added: []string{"alice"}},
+					Members:     orgs.Changes{Added: []string{"charlie"}},
+				}}))
+				Expect(string(plan.After)).To(HaveSuffix("      new:\n        description: The new team.\n" +
+					"        maintainers:\n        - alice\n        members:\n        - charlie\n"))
+			})
+		})
+
+		Context("when the team to create already exists", func() {
+			BeforeEach(func() {
+				s.Bindings[0].Create = &peribolos.TeamMetadata{}
+			})
+
+			It("should only update it", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.Drift()[0].Created).To(BeFalse())
+			})
+		})
+
+		Context("with child teams", func() {
+			BeforeEach(func() {
+				store.config = []byte(config + `        teams:
+          web:
+            members:
+            - bob
+`)
+			})
+
+			Context("when the team is addressed by its path", func() {
+				BeforeEach(func() {
+					s.Bindings[0].Team = "app/web"
+				})
+
+				It("should update the child team", func() {
+					Expect(err).To(Succeed())
+					Expect(plan.Drift()[0].Maintainers).To(Equal(orgs.Changes{Added: []string{"alice"}}))
+					Expect(string(plan.After)).To(HaveSuffix("          web:\n            members:\n            - bob\n" +
+						"            - charlie\n            maintainers:\n            - alice\n"))
+				})
+			})
+
+			Context("when the team is addressed by its name", func() {
+				BeforeEach(func() {
+					s.Bindings[0].Team = "web"
+				})
+
+				It("should update the child team", func() {
+					Expect(err).To(Succeed())
+					Expect(string(plan.After)).To(ContainSubstring("            - bob\n            - charlie\n"))
+				})
+			})
+
+			Context("when the child team to create does not exist", func() {
+				BeforeEach(func() {
+					s.Bindings[0].Team = "app/new"
+					s.Bindings[0].Create = &peribolos.TeamMetadata{}
+				})
+
+				It("should create it under its parent", func() {
+					Expect(err).To(Succeed())
+					Expect(plan.Drift()[0].Created).To(BeTrue())
+					Expect(string(plan.After)).To(HaveSuffix("          new:\n            maintainers:\n" +
+						"            - alice\n            members:\n            - charlie\n"))
+				})
+			})
+		})
+
+		Context("when the team of an optional binding does not exist", func() {
+			BeforeEach(func() {
+				s.Bindings = append(s.Bindings, syncer.Binding{
+					Org: "acme", Team: "unknown", Source: &fakeSource{err: errors.New("unused")}, Optional: true,
+				})
+			})
+
+			It("should skip the binding", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.Changes).To(HaveLen(1))
+				Expect(plan.Changes[0].Team).To(Equal("app"))
+				Expect(plan.Skipped).To(Equal([]string{"unknown"}))
+			})
+		})
+
+		Context("when the people added are not organization members", func() {
+			BeforeEach(func() {
+				store.config = []byte(strings.Replace(config, "    teams:", "    members:\n    - alice\n    teams:", 1))
+			})
+
+			It("should leave the organization members by default", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.Changes[0].OrgMembers).To(BeEmpty())
+			})
+
+			Context("when they are added", func() {
+				BeforeEach(func() {
+					s.OrgMembership = syncer.OrgMembershipAdd
+				})
+
+				It("should add them to the organization members", func() {
+					Expect(err).To(Succeed())
+					Expect(plan.Changes[0].OrgMembers).To(Equal([]string{"charlie"}))
+					Expect(string(plan.After)).To(ContainSubstring("    members:\n    - alice\n    - charlie\n"))
+				})
+			})
+
+			Context("when they are required", func() {
+				BeforeEach(func() {
+					s.OrgMembership = syncer.OrgMembershipRequire
+				})
+
+				It("should fail listing them", func() {
+					Expect(err).To(MatchError(ContainSubstring("team app: charlie")))
+				})
+			})
+		})
+
+		Context("when the organization membership is not managed by the config", func() {
+			Context("when the people are added", func() {
+				BeforeEach(func() {
+					s.OrgMembership = syncer.OrgMembershipAdd
+				})
+
+				It("should leave the organization without members", func() {
+					Expect(err).To(Succeed())
+					Expect(plan.Changes[0].OrgMembers).To(BeEmpty())
+					Expect(string(plan.After)).ToNot(ContainSubstring("\n    members:"))
+				})
+			})
+
+			Context("when the people are required", func() {
+				BeforeEach(func() {
+					s.OrgMembership = syncer.OrgMembershipRequire
+				})
+
+				It("should not fail", func() {
+					Expect(err).To(Succeed())
+				})
+			})
+		})
+
+		Context("with a derived binding", func() {
+			BeforeEach(func() {
+				store.config = []byte(config + "      all:\n        members:\n        - bob\n")
+				s.Bindings = append([]syncer.Binding{{
+					Org: "acme", Team: "all", Source: &teamSource{team: "app"}, Derived: true,
+				}}, s.Bindings...)
+			})
+
+			It("should load its people after the changes of the other bindings", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.Changes).To(HaveLen(2))
+				Expect(plan.Changes[0].Team).To(Equal("all"))
+				Expect(plan.Changes[0].Members.Added).To(Equal([]string{"charlie"}))
+			})
+		})
+	})
+
+	Context("when synchronizing", func() {
+		JustBeforeEach(func() {
+			plan, err = s.Sync()
+		})
+
+		It("should save and publish the changes", func() {
+			Expect(err).To(Succeed())
+			Expect(store.saved).To(Equal(plan.After))
+			Expect(publisher.published).To(Equal(plan))
+		})
+
+		Context("when the team is already in sync", func() {
+			BeforeEach(func() {
+				source.people = &syncer.People{Approvers: []string{"alice"}, Reviewers: []string{"bob"}}
+			})
+
+			It("should neither save nor publish", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.InSync()).To(BeTrue())
+				Expect(store.saved).To(BeNil())
+				Expect(publisher.published).To(BeNil())
+			})
+		})
+
+		Context("when the changes are beyond the guardrails", func() {
+			BeforeEach(func() {
+				s.Reconcile = true
+				source.people = &syncer.People{Reviewers: []string{"charlie"}}
+				s.Guardrails = &syncer.Guardrails{MaxRemoved: syncer.Limit{Value: 1}}
+			})
+
+			It("should neither save nor publish", func() {
+				Expect(syncer.IsGuardrailsError(err)).To(BeTrue())
+				Expect(err).To(MatchError(ContainSubstring("team app: removes 2 of 2 handles, more than the maximum of 1")))
+				Expect(err).To(MatchError(ContainSubstring("team app: removes every maintainer")))
+				Expect(store.saved).To(BeNil())
+				Expect(publisher.published).To(BeNil())
+			})
+
+			Context("when they are overridden", func() {
+				BeforeEach(func() {
+					s.Guardrails.Override = true
+				})
+
+				It("should save and publish the changes, reporting the violations", func() {
+					Expect(err).To(Succeed())
+					Expect(plan.Violations).To(HaveLen(2))
+					Expect(store.saved).To(Equal(plan.After))
+				})
+			})
+		})
+
+		Context("when the changes make the config invalid", func() {
+			BeforeEach(func() {
+				source.people = &syncer.People{Approvers: []string{"alice"}, Reviewers: []string{"-charlie"}}
+				s.Validate = true
+			})
+
+			It("should neither save nor publish", func() {
+				Expect(err).To(MatchError(ContainSubstring("-charlie in members is not a valid GitHub login")))
+				Expect(store.saved).To(BeNil())
+				Expect(publisher.published).To(BeNil())
+			})
+		})
+
+		Context("when the people added are not organization members", func() {
+			BeforeEach(func() {
+				store.config = []byte(strings.Replace(config, "    teams:", "    members:\n    - alice\n    - bob\n    teams:", 1))
+				s.Validate = true
+			})
+
+			It("should save and publish the changes by default", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.Findings).To(BeEmpty())
+				Expect(store.saved).To(Equal(plan.After))
+			})
+
+			Context("when they are added", func() {
+				BeforeEach(func() {
+					s.OrgMembership = syncer.OrgMembershipAdd
+				})
+
+				It("should save the changes with the new organization members", func() {
+					Expect(err).To(Succeed())
+					Expect(string(store.saved)).To(ContainSubstring("    - bob\n    - charlie\n"))
+				})
+			})
+		})
+
+		Context("when an approver is already a maintainer of the team", func() {
+			BeforeEach(func() {
+				source.people = &syncer.People{Approvers: []string{"alice", "carol"}}
+				s.Bindings[0].MapRoles = false
+				s.Validate = true
+			})
+
+			It("should only add the other approvers as members", func() {
+				Expect(err).To(Succeed())
+				Expect(plan.Findings).To(BeEmpty())
+				Expect(plan.Changes[0].Members.Added).To(Equal([]string{"carol"}))
+				Expect(store.saved).To(Equal(plan.After))
+			})
+		})
+
+		Context("without publisher", func() {
+			BeforeEach(func() {
+				s.Publisher = nil
+			})
+
+			It("should only save the changes", func() {
+				Expect(err).To(Succeed())
+				Expect(store.saved).To(Equal(plan.After))
+			})
+		})
+	})
+})